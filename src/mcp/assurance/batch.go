@@ -0,0 +1,76 @@
+package assurance
+
+import (
+	"context"
+)
+
+// Batch evaluates many holons against one preloaded Graph. Results are memoized
+// across calls, so each holon is computed once however many parents share it.
+type Batch struct {
+	calc  *Calculator
+	graph *Graph
+	ev    *evaluator
+}
+
+// NewBatch loads the whole graph from the database for batch evaluation
+func (c *Calculator) NewBatch(ctx context.Context) (*Batch, error) {
+	g, err := LoadGraph(ctx, c.DB)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewBatchFor(g), nil
+}
+
+// NewBatchFor evaluates against an existing Graph snapshot
+func (c *Calculator) NewBatchFor(g *Graph) *Batch {
+	return &Batch{calc: c, graph: g, ev: c.newEvaluator(g)}
+}
+
+// Graph returns the snapshot the batch evaluates
+func (b *Batch) Graph() *Graph {
+	return b.graph
+}
+
+// Report returns the (memoized) assurance report for a holon
+func (b *Batch) Report(ctx context.Context, holonID string) (*AssuranceReport, error) {
+	return b.ev.evaluate(ctx, holonID)
+}
+
+// All evaluates every holon in the graph. Dependencies finish before their dependents
+// (DFS post-order), so each report is built from already-memoized dependency results.
+func (b *Batch) All(ctx context.Context) (map[string]*AssuranceReport, error) {
+	reports := make(map[string]*AssuranceReport, len(b.graph.holons))
+	for _, id := range b.graph.HolonIDs() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report, err := b.ev.evaluate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		reports[id] = report
+	}
+	return reports, nil
+}
+
+// WriteCache stores cached_r_score for every holon evaluated so far in one transaction
+func (b *Batch) WriteCache(ctx context.Context) error {
+	return b.calc.writeCache(ctx, b.ev.memo)
+}
+
+// CalculateAll loads the graph once, evaluates every holon and writes the cache in a
+// single transaction
+func (c *Calculator) CalculateAll(ctx context.Context) (map[string]*AssuranceReport, error) {
+	b, err := c.NewBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := b.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.WriteCache(ctx); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
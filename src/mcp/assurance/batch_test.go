@@ -0,0 +1,221 @@
+package assurance
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// seedDiamond builds A → {B, C} → D, where D is degraded and reached through both B and C
+func seedDiamond(t *testing.T, db *sql.DB) {
+	t.Helper()
+	future := time.Now().Add(24 * time.Hour)
+	for _, id := range []string{"A", "B", "C", "D"} {
+		verdict := "pass"
+		if id == "D" {
+			verdict = "degrade"
+		}
+		if _, err := db.Exec("INSERT INTO holons (id) VALUES (?)", id); err != nil {
+			t.Fatalf("failed to insert holon: %v", err)
+		}
+		if _, err := db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES (?, ?, ?, ?)", "e-"+id, id, verdict, future); err != nil {
+			t.Fatalf("failed to insert evidence: %v", err)
+		}
+	}
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('C', 'A', 'componentOf', 2)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'D', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('C', 'D', 'dependsOn', 3)")
+}
+
+// seedCycle puts D on top of the cycle E ⇄ F, where E passes and F is degraded
+func seedCycle(t *testing.T, db *sql.DB) {
+	t.Helper()
+	future := time.Now().Add(24 * time.Hour)
+	for id, verdict := range map[string]string{"E": "pass", "F": "degrade"} {
+		if _, err := db.Exec("INSERT INTO holons (id) VALUES (?)", id); err != nil {
+			t.Fatalf("failed to insert holon: %v", err)
+		}
+		if _, err := db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES (?, ?, ?, ?)", "e-"+id, id, verdict, future); err != nil {
+			t.Fatalf("failed to insert evidence: %v", err)
+		}
+	}
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('D', 'E', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('E', 'F', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('F', 'E', 'dependsOn', 3)")
+}
+
+func TestCalculateReliability_DiamondIsNotACycle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedDiamond(t, db)
+
+	report, err := New(db).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// D (0.5) reaches A through B (CL3) and C (CL2: 0.5 - 0.1); the second path is not a cycle
+	if math.Abs(report.FinalScore-0.4) > 1e-9 {
+		t.Errorf("Expected 0.4 through the shared dependency, got %f", report.FinalScore)
+	}
+	if report.WeakestLink != "C" {
+		t.Errorf("Expected weakest link C, got %s", report.WeakestLink)
+	}
+	for _, f := range report.Factors {
+		if strings.Contains(f, "Cycle") {
+			t.Errorf("Diamond reported as cycle: %v", report.Factors)
+		}
+	}
+}
+
+func TestCalculateAll_MatchesSingleCalculation(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		seed    []func(*testing.T, *sql.DB)
+		reports int
+	}{
+		{"diamond", []func(*testing.T, *sql.DB){seedDiamond}, 4},
+		{"cycle", []func(*testing.T, *sql.DB){seedDiamond, seedCycle}, 6},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			defer db.Close()
+			for _, seed := range tc.seed {
+				seed(t, db)
+			}
+
+			calc := New(db)
+			reports, err := calc.CalculateAll(context.Background())
+			if err != nil {
+				t.Fatalf("CalculateAll failed: %v", err)
+			}
+			if len(reports) != tc.reports {
+				t.Fatalf("Expected %d reports, got %d", tc.reports, len(reports))
+			}
+
+			for id, batchReport := range reports {
+				var cached float64
+				if err := db.QueryRow("SELECT cached_r_score FROM holons WHERE id = ?", id).Scan(&cached); err != nil {
+					t.Fatalf("failed to read cache: %v", err)
+				}
+				if cached != batchReport.FinalScore {
+					t.Errorf("%s: cached %.2f, expected %.2f", id, cached, batchReport.FinalScore)
+				}
+
+				// A fresh calculation starts the walk at id, so it enters a cycle from another side
+				single, err := calc.CalculateReliability(context.Background(), id)
+				if err != nil {
+					t.Fatalf("CalculateReliability(%s) failed: %v", id, err)
+				}
+				if single.FinalScore != batchReport.FinalScore || single.WeakestLink != batchReport.WeakestLink {
+					t.Errorf("%s: batch (%.2f, %s) differs from single (%.2f, %s)",
+						id, batchReport.FinalScore, batchReport.WeakestLink, single.FinalScore, single.WeakestLink)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateReliability_CycleIsOrderIndependent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedCycle(t, db)
+
+	calc := New(db)
+	e, err := calc.CalculateReliability(context.Background(), "E")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	f, err := calc.CalculateReliability(context.Background(), "F")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	// The weakest member F (0.5) minus the cycle penalty (0.5) caps both, whichever is asked first
+	if e.FinalScore != 0 || f.FinalScore != 0 {
+		t.Errorf("Expected 0 for both members of the cycle, got E=%.2f F=%.2f", e.FinalScore, f.FinalScore)
+	}
+}
+
+func TestBatch_MemoizesSharedDependencies(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedDiamond(t, db)
+
+	batch, err := New(db).NewBatch(context.Background())
+	if err != nil {
+		t.Fatalf("NewBatch failed: %v", err)
+	}
+	a, err := batch.Report(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	d, err := batch.Report(context.Background(), "D")
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	again, _ := batch.Report(context.Background(), "D")
+	if d != again {
+		t.Error("Expected the same memoized report for D")
+	}
+	if math.Abs(a.FinalScore-0.4) > 1e-9 {
+		t.Errorf("Expected 0.4 for A, got %f", a.FinalScore)
+	}
+}
+
+func TestGraph_AddRelationDeduplicates(t *testing.T) {
+	g := NewGraph()
+	g.AddRelation("B", "A", "componentOf", 3)
+	g.AddRelation("A", "C", "dependsOn", 2)
+	g.AddRelation("B", "A", "componentOf", 3)
+	g.AddRelation("X", "A", "memberOf", 3)
+	g.AddRelation("X", "A", "memberOf", 3)
+
+	deps, _ := g.Dependencies(context.Background(), "A")
+	if len(deps) != 2 || deps[0].ID != "B" || deps[1].ID != "C" {
+		t.Errorf("Expected sorted unique deps [B C], got %+v", deps)
+	}
+	members, _ := g.Members(context.Background(), "A")
+	if len(members) != 1 {
+		t.Errorf("Expected one member, got %v", members)
+	}
+}
+
+func TestLoadGraph_MissingCongruenceLevelIsCL3(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO holons (id) VALUES ('A'), ('B')")
+	if _, err := db.Exec("INSERT INTO relations (source_id, target_id, relation_type) VALUES ('A', 'B', 'dependsOn')"); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := LoadGraph(context.Background(), db)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	for name, src := range map[string]GraphSource{"graph": g, "database": dbSource{db: db}} {
+		deps, err := src.Dependencies(context.Background(), "A")
+		if err != nil {
+			t.Fatalf("%s: Dependencies failed: %v", name, err)
+		}
+		if len(deps) != 1 || deps[0] != (Edge{ID: "B", CL: 3}) {
+			t.Errorf("%s: expected the relation as CL3, got %+v", name, deps)
+		}
+	}
+}
+
+func TestLoadGraph_ReturnsScanErrors(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO holons (id) VALUES ('A')")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', 'not a date')")
+
+	if _, err := LoadGraph(context.Background(), db); err == nil {
+		t.Error("Expected an unreadable evidence row to fail the load")
+	}
+}
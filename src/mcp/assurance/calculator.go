@@ -3,70 +3,387 @@ package assurance
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 )
 
+// Formality (F) bounds: F0 is informal prose, F9 is a machine-checked formal proof.
+const (
+	MinFormality = 0
+	MaxFormality = 9
+)
+
 // AssuranceReport contains details of the reliability calculation for AI explanation
 type AssuranceReport struct {
-	HolonID      string
-	FinalScore   float64
-	SelfScore    float64 // Score based on own evidence
-	WeakestLink  string  // ID of the dependency pulling the score down
-	DecayPenalty float64
-	Factors      []string // Textual explanations for AI
+	HolonID       string
+	FinalScore    float64
+	SelfScore     float64    // Score based on own evidence
+	Interval      Interval   // Credible interval of FinalScore, propagated through WLNK like the point estimate
+	SelfInterval  Interval   // Credible interval of SelfScore from the evidence counts
+	WeakestLink   string     // ID of the dependency pulling the score down
+	Formality     int        // Effective F: min(F) over self and dependencies
+	SelfFormality int        // F declared on the holon itself
+	Scope         ClaimScope // Effective G: self ∩ dependencies ∩ (∪ memberOf alternatives)
+	SelfScope     ClaimScope // G declared on the holon itself
+	DecayPenalty  float64
+	Waivers       []AppliedWaiver // Active waivers that suspended decay, including those of dependencies
+	Cycles        []string        // Holons of the dependency cycles (scored with CyclePenalty) at or below this one
+	PolicyVersion string          // Version of the Policy that produced this report
+	Factors       []string        // Textual explanations for AI
+}
+
+// AppliedWaiver records an active waiver that kept expired evidence from decaying
+type AppliedWaiver struct {
+	EvidenceID string
+	HolonID    string
+	Until      time.Time
 }
 
 // Calculator handles assurance logic
 type Calculator struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Policy *Policy
+	Now    func() time.Time // Clock for evidence decay; nil means time.Now
+	// ReadOnly evaluates without writing cached_r_score
+	ReadOnly bool
 }
 
-// New creates a new Calculator
+// New creates a new Calculator with the built-in policy
 func New(db *sql.DB) *Calculator {
-	return &Calculator{DB: db}
+	return &Calculator{DB: db, Policy: DefaultPolicy()}
 }
 
-// CalculateReliability calculates R for a holon (public API)
+func (c *Calculator) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// NewWithPolicy creates a Calculator using the given policy (nil means built-in)
+func NewWithPolicy(db *sql.DB, policy *Policy) *Calculator {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	return &Calculator{DB: db, Policy: policy}
+}
+
+// CalculateReliability calculates R for a holon (public API).
+// The graph is read lazily; every holon evaluated along the way is cached in one transaction.
 func (c *Calculator) CalculateReliability(ctx context.Context, holonID string) (*AssuranceReport, error) {
-	visited := make(map[string]bool)
-	return c.calculateReliabilityWithVisited(ctx, holonID, visited)
+	ev := c.newEvaluator(dbSource{db: c.DB})
+	report, err := ev.evaluate(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writeCache(ctx, ev.memo); err != nil {
+		report.Factors = append(report.Factors, "Warning: cache update failed")
+	}
+	return report, nil
+}
+
+// Evaluate calculates R for a holon against any GraphSource without touching the cache
+func (c *Calculator) Evaluate(ctx context.Context, src GraphSource, holonID string) (*AssuranceReport, error) {
+	return c.newEvaluator(src).evaluate(ctx, holonID)
+}
+
+// evaluator condenses the dependency graph into strongly connected components (Tarjan) and
+// evaluates them dependencies first. Finished holons are memoized, so a dependency shared by
+// several parents (a diamond) is evaluated once. The holons of a cycle are evaluated together
+// by one rule, so their scores do not depend on where the walk entered the cycle.
+type evaluator struct {
+	calc   *Calculator
+	src    GraphSource
+	memo   map[string]*AssuranceReport
+	active map[string]bool // Holons of the components being evaluated
+}
+
+func (c *Calculator) newEvaluator(src GraphSource) *evaluator {
+	return &evaluator{
+		calc:   c,
+		src:    src,
+		memo:   make(map[string]*AssuranceReport),
+		active: make(map[string]bool),
+	}
+}
+
+func (ev *evaluator) evaluate(ctx context.Context, holonID string) (*AssuranceReport, error) {
+	if report, ok := ev.memo[holonID]; ok {
+		return report, nil
+	}
+	components, deps, err := ev.condense(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		if _, done := ev.memo[component[0]]; done {
+			// Already evaluated as a memberOf alternative of an earlier component
+			continue
+		}
+		if err := ev.evaluateComponent(ctx, component, deps); err != nil {
+			return nil, err
+		}
+	}
+	return ev.memo[holonID], nil
+}
+
+// condense runs Tarjan's algorithm over the dependencies reachable from holonID that are not
+// memoized yet. Components come out dependencies first, each with its holons sorted.
+func (ev *evaluator) condense(ctx context.Context, holonID string) ([][]string, map[string][]Edge, error) {
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	deps := make(map[string][]Edge)
+	var stack []string
+	var components [][]string
+
+	var strongConnect func(v string) error
+	strongConnect = func(v string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ev.active[v] {
+			// Reached through memberOf from a holon whose own evaluation is under way
+			return fmt.Errorf("holon %s is still being evaluated", v)
+		}
+		edges, err := ev.src.Dependencies(ctx, v)
+		if err != nil {
+			return err
+		}
+		deps[v] = edges
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, e := range edges {
+			if _, done := ev.memo[e.ID]; done {
+				continue
+			}
+			if _, seen := indices[e.ID]; !seen {
+				if err := strongConnect(e.ID); err != nil {
+					return err
+				}
+				lowlink[v] = min(lowlink[v], lowlink[e.ID])
+			} else if onStack[e.ID] {
+				lowlink[v] = min(lowlink[v], indices[e.ID])
+			}
+		}
+
+		if lowlink[v] != indices[v] {
+			return nil
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+		return nil
+	}
+
+	if err := strongConnect(holonID); err != nil {
+		return nil, nil, err
+	}
+	return components, deps, nil
+}
+
+// linkedReport is a report whose dependencies are being folded in
+type linkedReport struct {
+	*AssuranceReport
+	hasDeps        bool
+	minDepScore    float64
+	minDepInterval Interval
+}
+
+// link folds one dependency into the report: R and its interval through the CL penalty, F and G unchanged by CL
+func (ev *evaluator) link(r *linkedReport, dep Edge, score float64, interval Interval, formality int, scope ClaimScope) {
+	c := ev.calc
+	r.hasDeps = true
+
+	// CL Penalty: Φ(CL) from the policy table, applied subtractively or multiplicatively
+	effectiveR := c.Policy.Penalty.Apply(score, dep.CL)
+	if effectiveR < r.minDepScore {
+		r.minDepScore = effectiveR
+		r.WeakestLink = dep.ID
+	}
+	// Both bounds go through the same monotone penalty, so the interval keeps bracketing R
+	r.minDepInterval.Lower = math.Min(r.minDepInterval.Lower, c.Policy.Penalty.Apply(interval.Lower, dep.CL))
+	r.minDepInterval.Upper = math.Min(r.minDepInterval.Upper, c.Policy.Penalty.Apply(interval.Upper, dep.CL))
+	if c.Policy.Penalty.Phi(dep.CL) > 0 {
+		r.Factors = append(r.Factors, "CL Penalty applied for "+dep.ID)
+	}
+
+	// F is not reduced by CL: congruence affects trust, not rigor
+	if formality < r.Formality {
+		r.Formality = formality
+		r.Factors = append(r.Factors, fmt.Sprintf("Formality capped at F%d by %s", formality, dep.ID))
+	}
+
+	// G of a serial chain is the intersection: the claim holds only where its dependency holds
+	narrowed := r.Scope.Intersect(scope)
+	if narrowed.String() != r.Scope.String() {
+		r.Factors = append(r.Factors, fmt.Sprintf("Scope narrowed by %s to %s", dep.ID, narrowed))
+	}
+	r.Scope = narrowed
 }
 
-// calculateReliabilityWithVisited is the internal implementation with cycle detection
-func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonID string, visited map[string]bool) (*AssuranceReport, error) {
-	// Cycle detection: if already visited, return neutral score to break cycle
-	if visited[holonID] {
-		return &AssuranceReport{
-			HolonID:    holonID,
-			FinalScore: 1.0, // Neutral - don't penalize for cycle
-			SelfScore:  1.0,
-			Factors:    []string{"Cycle detected, skipping re-evaluation"},
-		}, nil
+// finish applies the Weakest Link Principle (WLNK): the final rating cannot be higher than
+// the weakest link (self or dependency)
+func (r *linkedReport) finish() {
+	if r.hasDeps {
+		r.FinalScore = math.Min(r.SelfScore, r.minDepScore)
+		r.Interval = Interval{
+			Lower: math.Min(r.SelfInterval.Lower, r.minDepInterval.Lower),
+			Upper: math.Min(r.SelfInterval.Upper, r.minDepInterval.Upper),
+		}
+	} else {
+		r.FinalScore = r.SelfScore
+		r.Interval = r.SelfInterval
+	}
+}
+
+// evaluateComponent evaluates the holons of one component, whose dependencies outside it are
+// all memoized, and memoizes them
+func (ev *evaluator) evaluateComponent(ctx context.Context, component []string, deps map[string][]Edge) error {
+	c := ev.calc
+	inComponent := make(map[string]bool, len(component))
+	for _, id := range component {
+		inComponent[id] = true
+		ev.active[id] = true
+	}
+	defer func() {
+		for _, id := range component {
+			delete(ev.active, id)
+		}
+	}()
+
+	cyclic := len(component) > 1
+	reports := make([]*linkedReport, len(component))
+	for i, id := range component {
+		report, err := ev.selfReport(ctx, id)
+		if err != nil {
+			return err
+		}
+		r := &linkedReport{AssuranceReport: report, minDepScore: 1.0, minDepInterval: Interval{Lower: 1.0, Upper: 1.0}}
+
+		// 2. Calculate Dependencies Score (Weakest Link + CL Penalty)
+		// B.3: R_eff = max(0, min(R_dep) - Penalty(CL))
+		// Relation directionality is resolved by the GraphSource (see Dependencies)
+		for _, d := range deps[id] {
+			if inComponent[d.ID] {
+				cyclic = true
+				continue
+			}
+			dep, ok := ev.memo[d.ID]
+			if !ok {
+				dep = &AssuranceReport{}
+			}
+			ev.link(r, d, dep.FinalScore, dep.Interval, dep.Formality, dep.Scope)
+			r.Waivers = append(r.Waivers, dep.Waivers...)
+			for _, id := range dep.Cycles {
+				if !containsString(r.Cycles, id) {
+					r.Cycles = append(r.Cycles, id)
+				}
+			}
+		}
+		r.finish()
+		reports[i] = r
+	}
+
+	if cyclic {
+		ev.linkCycle(component, reports, deps, inComponent)
+	}
+
+	for _, r := range reports {
+		if err := ev.applyMembers(ctx, r.AssuranceReport); err != nil {
+			return err
+		}
+		if r.Scope.IsEmpty() {
+			r.Factors = append(r.Factors, "Scope is empty: no context slice satisfies all dependencies")
+		}
+		r.PolicyVersion = c.Policy.Version
+		ev.memo[r.HolonID] = r.AssuranceReport
 	}
-	visited[holonID] = true
+	return nil
+}
 
-	report := &AssuranceReport{HolonID: holonID}
+// linkCycle folds the dependencies inside a cycle. Every edge inside it contributes the weakest
+// member's score outside the cycle, reduced by the cycle penalty, so a cycle can never inflate
+// R_eff and every member gets the same answer whichever holon is asked about first.
+func (ev *evaluator) linkCycle(component []string, reports []*linkedReport, deps map[string][]Edge, inComponent map[string]bool) {
+	penalty := ev.calc.Policy.CyclePenalty
+	weakest := reports[0]
+	formality := reports[0].Formality
+	scope := reports[0].Scope
+	var waivers []AppliedWaiver
+	for _, r := range reports {
+		if r.FinalScore < weakest.FinalScore {
+			weakest = r
+		}
+		formality = min(formality, r.Formality)
+		scope = scope.Intersect(r.Scope)
+		waivers = append(waivers, r.Waivers...)
+	}
+	score := math.Max(0, weakest.FinalScore-penalty)
+	interval := Interval{Lower: math.Max(0, weakest.Interval.Lower-penalty), Upper: math.Max(0, weakest.Interval.Upper-penalty)}
 
+	for _, r := range reports {
+		for _, d := range deps[r.HolonID] {
+			if !inComponent[d.ID] {
+				continue
+			}
+			ev.link(r, d, score, interval, formality, scope)
+			r.Factors = append(r.Factors, fmt.Sprintf("Cycle: %s depends on %s (weakest in cycle %s at %.2f, cycle penalty %.2f applied)",
+				r.HolonID, d.ID, weakest.HolonID, weakest.FinalScore, penalty))
+		}
+		r.finish()
+		r.Waivers = waivers
+		for _, id := range component {
+			if !containsString(r.Cycles, id) {
+				r.Cycles = append(r.Cycles, id)
+			}
+		}
+	}
+}
+
+// selfReport starts a report from the holon's own evidence, formality and scope
+func (ev *evaluator) selfReport(ctx context.Context, holonID string) (*AssuranceReport, error) {
+	c := ev.calc
+	report := &AssuranceReport{HolonID: holonID, PolicyVersion: c.Policy.Version}
 	// 1. Calculate Self Score (based on Evidence)
-	// B.3.4: Check for expired evidence
-	rows, err := c.DB.QueryContext(ctx, "SELECT verdict, valid_until FROM evidence WHERE holon_id = ?", holonID)
+	// B.3.4: Check for expired evidence; an active waiver suspends decay until it expires
+	waivers, err := ev.src.Waivers(ctx, holonID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close() //nolint:errcheck
-
-	var totalScore, count float64
-	for rows.Next() {
-		var verdict string
-		var validUntil *time.Time
-		if err := rows.Scan(&verdict, &validUntil); err != nil {
-			continue
+	waivedUntil := make(map[string]time.Time)
+	for _, w := range waivers {
+		if w.Until.After(c.now()) && w.Until.After(waivedUntil[w.EvidenceID]) {
+			waivedUntil[w.EvidenceID] = w.Until
 		}
+	}
 
+	evidence, err := ev.src.Evidence(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []weightedScore
+	for _, e := range evidence {
 		score := 0.0
-		switch strings.ToLower(verdict) {
+		switch strings.ToLower(e.Verdict) {
 		case "pass":
 			score = 1.0
 		case "degrade":
@@ -75,106 +392,140 @@ func (c *Calculator) calculateReliabilityWithVisited(ctx context.Context, holonI
 			score = 0.0
 		}
 
-		// Evidence Decay Logic
-		if validUntil != nil && time.Now().After(*validUntil) {
-			report.Factors = append(report.Factors, "Evidence expired (Decay applied)")
-			score = 0.1                // Penalty for expiration, not zero but close
-			report.DecayPenalty += 0.9 // Track how much was lost
+		// Evidence Decay Logic: the curve for this evidence type starts at valid_until
+		expired := e.ValidUntil != nil && c.now().After(*e.ValidUntil)
+		if until, waived := waivedUntil[e.ID]; expired && waived {
+			report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s waived until %s (decay suspended)", e.ID, until.Format("2006-01-02")))
+			report.Waivers = append(report.Waivers, AppliedWaiver{EvidenceID: e.ID, HolonID: holonID, Until: until})
+		} else if expired {
+			curve := c.Policy.Decay.For(e.Type)
+			elapsed := c.now().Sub(*e.ValidUntil)
+			decayed := curve.Apply(score, elapsed)
+			if e.Exhausted {
+				decayed = math.Min(score, curve.Floor)
+			}
+			switch {
+			case curve.Kind == DecayStep:
+				report.Factors = append(report.Factors, "Evidence expired (Decay applied)")
+			case e.Exhausted:
+				report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s fully decayed (%s): %.2f -> %.2f", e.ID, curve.Kind, score, decayed))
+			default:
+				report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s decaying (%s, %d days past valid_until): %.2f -> %.2f",
+					e.ID, curve.Kind, int(elapsed.Hours()/24), score, decayed))
+			}
+			report.DecayPenalty += score - decayed // Track how much was lost
+			score = decayed
+		}
+
+		weight := c.Policy.Evidence.Weight(e.Type, e.Level, e.CarrierRef)
+		if weight != 1.0 {
+			report.Factors = append(report.Factors, fmt.Sprintf("Evidence %s weighted %.2f (type=%s, level=%s, carrier=%s)",
+				e.ID, weight, e.Type, e.Level, CarrierKind(e.CarrierRef)))
 		}
-		totalScore += score
-		count++
+		items = append(items, weightedScore{score: score, weight: weight})
 	}
 
-	if count > 0 {
-		report.SelfScore = totalScore / count // Or other aggregation logic
+	if len(items) > 0 {
+		report.SelfScore = c.Policy.Evidence.Aggregate(items)
+		report.Factors = append(report.Factors, fmt.Sprintf("Self score: %s of %d evidence", c.Policy.Evidence.Aggregation, len(items)))
 	} else {
 		report.SelfScore = 0.0 // L0: Unsubstantiated
 		report.Factors = append(report.Factors, "No evidence found (L0)")
 	}
+	report.SelfInterval = CredibleInterval(items, c.Policy.Credibility, report.SelfScore)
 
-	// 2. Calculate Dependencies Score (Weakest Link + CL Penalty)
-	// B.3: R_eff = max(0, min(R_dep) - Penalty(CL))
-	// Relation directionality:
-	//   - componentOf: Part → Whole (source is part OF target)
-	//   - dependsOn:   Dependent → Dependency (source DEPENDS ON target)
-	// When calculating reliability for holonID:
-	//   - componentOf: find rows where target_id = holonID, dependency is source_id
-	//   - dependsOn:   find rows where source_id = holonID, dependency is target_id
-	depRows, err := c.DB.QueryContext(ctx, `
-		SELECT source_id AS dep_id, congruence_level FROM relations
-		WHERE target_id = ? AND relation_type = 'componentOf'
-		UNION
-		SELECT target_id AS dep_id, congruence_level FROM relations
-		WHERE source_id = ? AND relation_type = 'dependsOn'`, holonID, holonID)
-
+	// 1b. Formality and scope of the holon itself (missing holon row counts as F0, unbounded G)
+	holon, _, err := ev.src.Holon(ctx, holonID)
 	if err != nil {
 		return nil, err
 	}
+	report.SelfFormality = clampFormality(holon.Formality)
+	report.Formality = report.SelfFormality
 
-	// Collect deps first to avoid holding cursor during recursive calls
-	type dep struct {
-		id string
-		cl int
+	selfScope, scopeErr := ParseScope(holon.Scope)
+	if scopeErr != nil {
+		// Legacy free-text scope: keep it readable but do not constrain G
+		report.Factors = append(report.Factors, fmt.Sprintf("Scope %q is free text, treated as unbounded", holon.Scope))
+		selfScope = ClaimScope{}
 	}
-	var deps []dep
-	for depRows.Next() {
-		var d dep
-		if err := depRows.Scan(&d.id, &d.cl); err != nil {
-			continue
-		}
-		deps = append(deps, d)
-	}
-	_ = depRows.Close()
+	report.SelfScope = selfScope
+	report.Scope = selfScope
+	return report, nil
+}
 
-	minDepScore := 1.0
-	for _, d := range deps {
-		// Recursive call for dependency with visited map for cycle detection
-		depReport, err := c.calculateReliabilityWithVisited(ctx, d.id, visited)
-		if err != nil {
-			depReport = &AssuranceReport{FinalScore: 0.0}
-		}
+// applyMembers narrows the report's scope by its memberOf alternatives
+func (ev *evaluator) applyMembers(ctx context.Context, report *AssuranceReport) error {
+	holonID := report.HolonID
 
-		// CL Penalty: CL=3 (0.0), CL=2 (0.1), CL=1 (0.4), CL=0 (0.9)
-		penalty := calculateCLPenalty(d.cl)
-		effectiveR := math.Max(0, depReport.FinalScore-penalty)
+	// 2b. memberOf alternatives: G of a decision context is the union of its members' G.
+	// Members do NOT propagate R (see WLNK) - only scope is aggregated here.
+	members, err := ev.src.Members(ctx, holonID)
+	if err != nil {
+		return err
+	}
 
-		if effectiveR < minDepScore {
-			minDepScore = effectiveR
-			report.WeakestLink = d.id
+	if len(members) > 0 {
+		var alternatives ClaimScope
+		hasAlternatives := false
+		for _, m := range members {
+			memberReport, err := ev.evaluate(ctx, m)
+			if err != nil {
+				continue
+			}
+			if !hasAlternatives {
+				alternatives = memberReport.Scope
+				hasAlternatives = true
+				continue
+			}
+			alternatives = alternatives.Union(memberReport.Scope)
 		}
-
-		if penalty > 0 {
-			report.Factors = append(report.Factors, "CL Penalty applied for "+d.id)
+		if hasAlternatives {
+			report.Scope = report.Scope.Intersect(alternatives)
 		}
 	}
+	return nil
+}
 
-	hasDeps := len(deps) > 0
+// writeCache stores cached_r_score for every evaluated holon in a single transaction
+func (c *Calculator) writeCache(ctx context.Context, reports map[string]*AssuranceReport) error {
+	if c.ReadOnly {
+		return nil
+	}
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
 
-	// 3. Weakest Link Principle (WLNK)
-	// The final rating cannot be higher than the weakest link (self or dependency)
-	if hasDeps {
-		report.FinalScore = math.Min(report.SelfScore, minDepScore)
-	} else {
-		report.FinalScore = report.SelfScore
+	stmt, err := tx.PrepareContext(ctx, "UPDATE holons SET cached_r_score = ? WHERE id = ?")
+	if err != nil {
+		return err
 	}
+	defer stmt.Close() //nolint:errcheck
 
-	// Update cache (non-critical, log warning on failure)
-	if _, err := c.DB.ExecContext(ctx, "UPDATE holons SET cached_r_score = ? WHERE id = ?", report.FinalScore, holonID); err != nil {
-		report.Factors = append(report.Factors, "Warning: cache update failed")
+	for id, report := range reports {
+		if _, err := stmt.ExecContext(ctx, report.FinalScore, id); err != nil {
+			return err
+		}
 	}
+	return tx.Commit()
+}
 
-	return report, nil
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
-func calculateCLPenalty(cl int) float64 {
-	switch cl {
-	case 3:
-		return 0.0
-	case 2:
-		return 0.1
-	case 1:
-		return 0.4
-	default:
-		return 0.9
+func clampFormality(f int) int {
+	if f < MinFormality {
+		return MinFormality
+	}
+	if f > MaxFormality {
+		return MaxFormality
 	}
+	return f
 }
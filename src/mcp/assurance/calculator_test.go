@@ -3,6 +3,8 @@ package assurance
 import (
 	"context"
 	"database/sql"
+	"math"
+	"reflect"
 	"testing"
 	"time"
 
@@ -18,9 +20,10 @@ func setupTestDB(t *testing.T) *sql.DB {
 	db.SetMaxOpenConns(1) // Ensure single connection to avoid issues
 
 	schema := `
-	CREATE TABLE holons (id TEXT PRIMARY KEY, cached_r_score REAL DEFAULT 0.0);
-	CREATE TABLE evidence (id TEXT PRIMARY KEY, holon_id TEXT, verdict TEXT, valid_until DATETIME);
+	CREATE TABLE holons (id TEXT PRIMARY KEY, cached_r_score REAL DEFAULT 0.0, formality INTEGER DEFAULT 0, scope TEXT);
+	CREATE TABLE evidence (id TEXT PRIMARY KEY, holon_id TEXT, type TEXT DEFAULT 'internal', verdict TEXT, assurance_level TEXT, carrier_ref TEXT, valid_until DATETIME);
 	CREATE TABLE relations (source_id TEXT, target_id TEXT, relation_type TEXT, congruence_level INTEGER);
+	CREATE TABLE waivers (id TEXT PRIMARY KEY, evidence_id TEXT, waived_until DATETIME);
 	`
 	if _, err := db.Exec(schema); err != nil {
 		t.Fatalf("failed to init schema: %v", err)
@@ -114,6 +117,151 @@ func TestCalculateReliability_CLPenalty(t *testing.T) {
 	}
 }
 
+func TestCalculateReliability_MultiplicativePolicy(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// A passes; B is degraded (0.5) and attached at CL1
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'degrade', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 1)")
+
+	report, err := New(db).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if math.Abs(report.FinalScore-0.1) > 1e-9 {
+		t.Errorf("Expected subtractive score 0.1, got %f", report.FinalScore)
+	}
+	if report.PolicyVersion != DefaultPolicyVersion {
+		t.Errorf("Expected policy version %s, got %s", DefaultPolicyVersion, report.PolicyVersion)
+	}
+
+	policy := DefaultPolicy()
+	policy.Version = "mult-test"
+	policy.Penalty.Mode = PenaltyMultiplicative
+	report, err = NewWithPolicy(db, policy).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if math.Abs(report.FinalScore-0.3) > 1e-9 {
+		t.Errorf("Expected multiplicative score 0.3, got %f", report.FinalScore)
+	}
+	if report.PolicyVersion != "mult-test" {
+		t.Errorf("Expected policy version mult-test, got %s", report.PolicyVersion)
+	}
+}
+
+func TestCalculateReliability_Waiver(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'B', 'pass', ?)", now.Add(-48*time.Hour))
+	_, _ = db.Exec("INSERT INTO waivers (id, evidence_id, waived_until) VALUES ('w-old', 'e1', ?)", now.Add(-24*time.Hour))
+	_, _ = db.Exec("INSERT INTO waivers (id, evidence_id, waived_until) VALUES ('w1', 'e1', ?)", now.Add(10*24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'A', 'pass', ?)", now.Add(30*24*time.Hour))
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 3)")
+
+	calc := New(db)
+	calc.Now = func() time.Time { return now }
+
+	// Active waiver: expired evidence counts as un-decayed, for B and for A through B
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if report.FinalScore != 1.0 {
+		t.Errorf("Expected waived score 1.0, got %f", report.FinalScore)
+	}
+	if len(report.Waivers) != 1 || report.Waivers[0].EvidenceID != "e1" || !report.Waivers[0].Until.Equal(now.Add(10*24*time.Hour)) {
+		t.Errorf("Expected waiver e1 with latest expiry in report, got %+v", report.Waivers)
+	}
+
+	// Waiver expired: decay applies again
+	calc.Now = func() time.Time { return now.Add(11 * 24 * time.Hour) }
+	report, err = calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if math.Abs(report.FinalScore-0.1) > 1e-9 {
+		t.Errorf("Expected decayed score 0.1 after waiver expiry, got %f", report.FinalScore)
+	}
+	if len(report.Waivers) != 0 {
+		t.Errorf("Expected no active waivers, got %+v", report.Waivers)
+	}
+}
+
+func TestCalculateReliability_FormalityWeakestLink(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, _ = db.Exec("INSERT INTO holons (id, formality) VALUES ('A', 5), ('B', 2)")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", time.Now().Add(24*time.Hour))
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e2', 'B', 'pass', ?)", time.Now().Add(24*time.Hour))
+
+	// B is component of A; CL does not reduce formality
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'componentOf', 1)")
+
+	calc := New(db)
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	if report.SelfFormality != 5 {
+		t.Errorf("Expected self formality F5, got F%d", report.SelfFormality)
+	}
+	if report.Formality != 2 {
+		t.Errorf("Expected effective formality F2 (min over dependencies), got F%d", report.Formality)
+	}
+
+	// Holon without a row defaults to F0
+	report, err = calc.CalculateReliability(context.Background(), "unknown")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if report.Formality != 0 {
+		t.Errorf("Expected F0 for unknown holon, got F%d", report.Formality)
+	}
+}
+
+func TestCalculateReliability_ScopePropagation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// A depends on B (serial: intersection); D groups alternatives M1, M2 (memberOf: union)
+	_, _ = db.Exec(`INSERT INTO holons (id, scope) VALUES
+		('A', 'service=payments,checkout; env=prod'),
+		('B', 'service=payments'),
+		('D', 'global'),
+		('M1', 'env=prod; region=eu'),
+		('M2', 'env=staging; region=eu')`)
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'B', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('M1', 'D', 'memberOf', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('M2', 'D', 'memberOf', 3)")
+
+	calc := New(db)
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if got := report.Scope.String(); got != "env=prod; service=payments" {
+		t.Errorf("Expected intersected scope, got %q", got)
+	}
+	if got := report.SelfScope.String(); got != "env=prod; service=checkout,payments" {
+		t.Errorf("Expected self scope unchanged, got %q", got)
+	}
+
+	report, err = calc.CalculateReliability(context.Background(), "D")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if got := report.Scope.String(); got != "env=prod,staging; region=eu" {
+		t.Errorf("Expected union of alternatives, got %q", got)
+	}
+}
+
 func TestCalculateReliability_CycleDetection(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -137,9 +285,12 @@ func TestCalculateReliability_CycleDetection(t *testing.T) {
 		t.Fatalf("CalculateReliability failed on cycle: %v", err)
 	}
 
-	// All have passing evidence, no CL penalty, cycle should not affect final score
-	// Each node has self-score 1.0, and deps also 1.0 (cycle broken by visited check)
-	if report.FinalScore != 1.0 {
-		t.Errorf("Expected score 1.0 (cycle handled gracefully), got %f", report.FinalScore)
+	// All have passing evidence and no CL penalty, but the cycle must not be free:
+	// edges inside it contribute the weakest member's score minus the cycle penalty (1.0 - 0.5)
+	if report.FinalScore != 0.5 {
+		t.Errorf("Expected score 0.5 (cycle penalty), got %f", report.FinalScore)
+	}
+	if !reflect.DeepEqual(report.Cycles, []string{"A", "B", "C"}) {
+		t.Errorf("Expected the cycle A, B, C to be reported, got %v", report.Cycles)
 	}
 }
@@ -0,0 +1,165 @@
+package assurance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DecayKind selects the shape of an evidence decay curve
+type DecayKind string
+
+const (
+	DecayStep        DecayKind = "step"        // Drops to the floor the moment valid_until passes
+	DecayLinear      DecayKind = "linear"      // Falls linearly to the floor over WindowDays
+	DecayExponential DecayKind = "exponential" // Halves the distance to the floor every HalfLifeDays
+)
+
+// DefaultDecayKey is the DecayPolicy entry used for evidence types without their own curve
+const DefaultDecayKey = "default"
+
+// DecayCurve describes how an evidence score decays once valid_until has passed
+type DecayCurve struct {
+	Kind         DecayKind `json:"curve"`
+	WindowDays   float64   `json:"window_days,omitempty"`    // linear only
+	HalfLifeDays float64   `json:"half_life_days,omitempty"` // exponential only
+	Floor        float64   `json:"floor"`                    // Score an expired pass converges to
+}
+
+// DecayPolicy maps evidence.type to its decay curve; the "default" entry covers other types
+type DecayPolicy map[string]DecayCurve
+
+func defaultDecayPolicy() DecayPolicy {
+	return DecayPolicy{
+		DefaultDecayKey: {Kind: DecayStep, Floor: 0.1},
+	}
+}
+
+// For returns the curve for an evidence type (keys are lower case, see Policy.normalize),
+// falling back to "default" and then to a step at 0.1
+func (dp DecayPolicy) For(evidenceType string) DecayCurve {
+	if curve, ok := dp[strings.ToLower(evidenceType)]; ok {
+		return curve
+	}
+	if curve, ok := dp[DefaultDecayKey]; ok {
+		return curve
+	}
+	return DecayCurve{Kind: DecayStep, Floor: 0.1}
+}
+
+func (dp DecayPolicy) validate() error {
+	for key, curve := range dp {
+		if curve.Floor < 0 || curve.Floor > 1 {
+			return fmt.Errorf("decay %q: floor must be within [0, 1], got %.2f", key, curve.Floor)
+		}
+		switch curve.Kind {
+		case DecayStep:
+		case DecayLinear:
+			if curve.WindowDays <= 0 {
+				return fmt.Errorf("decay %q: linear curve needs window_days > 0", key)
+			}
+		case DecayExponential:
+			if curve.HalfLifeDays <= 0 {
+				return fmt.Errorf("decay %q: exponential curve needs half_life_days > 0", key)
+			}
+		default:
+			return fmt.Errorf("decay %q: unknown curve %q (use %q, %q or %q)", key, curve.Kind, DecayStep, DecayLinear, DecayExponential)
+		}
+	}
+	return nil
+}
+
+// Retained returns the fraction (0..1) of the distance between score and floor
+// still retained after elapsed time past valid_until
+func (dc DecayCurve) Retained(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 1.0
+	}
+	days := elapsed.Hours() / 24
+	switch dc.Kind {
+	case DecayLinear:
+		return math.Max(0, 1-days/dc.WindowDays)
+	case DecayExponential:
+		return math.Pow(0.5, days/dc.HalfLifeDays)
+	default:
+		return 0.0
+	}
+}
+
+// Apply returns the decayed score. Decay never raises a score, so failed evidence stays failed.
+func (dc DecayCurve) Apply(score float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return score
+	}
+	return math.Min(score, dc.Floor+(score-dc.Floor)*dc.Retained(elapsed))
+}
+
+// DaysUntilBelow returns the first day (1..horizonDays) on which holonID's R_eff falls below
+// threshold. found is false if R_eff is already below threshold today or stays at or above it
+// for the whole horizon. Projections never write the score cache.
+func (c *Calculator) DaysUntilBelow(ctx context.Context, src GraphSource, holonID string, threshold float64, horizonDays int) (days int, found bool, err error) {
+	return c.NewForecast(src, horizonDays).DaysUntilBelow(ctx, holonID, threshold)
+}
+
+// Forecast projects R_eff of the holons of one graph forward by whole days. Decay and expiring
+// waivers only ever lower R_eff, so the first day below a threshold is found by bisection, and
+// each projected day is evaluated at most once for all holons asked about.
+type Forecast struct {
+	calc    *Calculator
+	src     GraphSource
+	base    time.Time
+	horizon int
+	days    map[int]*evaluator
+}
+
+// NewForecast starts a forecast from the calculator's clock over horizonDays days
+func (c *Calculator) NewForecast(src GraphSource, horizonDays int) *Forecast {
+	return &Forecast{calc: c, src: src, base: c.now(), horizon: horizonDays, days: make(map[int]*evaluator)}
+}
+
+// score returns holonID's R_eff day days from the start
+func (f *Forecast) score(ctx context.Context, holonID string, day int) (float64, error) {
+	ev, ok := f.days[day]
+	if !ok {
+		at := f.base.AddDate(0, 0, day)
+		projected := *f.calc
+		projected.Now = func() time.Time { return at }
+		ev = projected.newEvaluator(f.src)
+		f.days[day] = ev
+	}
+	report, err := ev.evaluate(ctx, holonID)
+	if err != nil {
+		return 0, err
+	}
+	return report.FinalScore, nil
+}
+
+// DaysUntilBelow is Calculator.DaysUntilBelow over the forecast's graph and horizon
+func (f *Forecast) DaysUntilBelow(ctx context.Context, holonID string, threshold float64) (days int, found bool, err error) {
+	today, err := f.score(ctx, holonID, 0)
+	if err != nil || today < threshold {
+		return 0, false, err
+	}
+	last, err := f.score(ctx, holonID, f.horizon)
+	if err != nil || last >= threshold {
+		return 0, false, err
+	}
+
+	// R_eff is at or above threshold on day above and below it on day below
+	above, below := 0, f.horizon
+	for below-above > 1 {
+		mid := (above + below) / 2
+		score, err := f.score(ctx, holonID, mid)
+		if err != nil {
+			return 0, false, err
+		}
+		if score < threshold {
+			below = mid
+		} else {
+			above = mid
+		}
+	}
+	return below, true, nil
+}
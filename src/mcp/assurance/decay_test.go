@@ -0,0 +1,157 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+func TestDecayCurve_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		curve    DecayCurve
+		score    float64
+		elapsed  time.Duration
+		expected float64
+	}{
+		{"not yet expired", DecayCurve{Kind: DecayStep, Floor: 0.1}, 1.0, -day, 1.0},
+		{"step", DecayCurve{Kind: DecayStep, Floor: 0.1}, 1.0, day, 0.1},
+		{"step never raises a fail", DecayCurve{Kind: DecayStep, Floor: 0.1}, 0.0, day, 0.0},
+		{"linear halfway", DecayCurve{Kind: DecayLinear, WindowDays: 30}, 1.0, 15 * day, 0.5},
+		{"linear past window", DecayCurve{Kind: DecayLinear, WindowDays: 30, Floor: 0.2}, 1.0, 60 * day, 0.2},
+		{"exponential one half-life", DecayCurve{Kind: DecayExponential, HalfLifeDays: 10}, 1.0, 10 * day, 0.5},
+		{"exponential with floor", DecayCurve{Kind: DecayExponential, HalfLifeDays: 10, Floor: 0.2}, 1.0, 20 * day, 0.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.curve.Apply(tt.score, tt.elapsed)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Apply(%.2f, %v) = %.4f, expected %.4f", tt.score, tt.elapsed, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecayPolicy_For(t *testing.T) {
+	dp := DecayPolicy{
+		DefaultDecayKey: {Kind: DecayStep, Floor: 0.1},
+		"research":      {Kind: DecayExponential, HalfLifeDays: 30},
+	}
+	if dp.For("Research").Kind != DecayExponential {
+		t.Error("Expected research evidence to use its own curve")
+	}
+	if dp.For("internal").Kind != DecayStep {
+		t.Error("Expected other types to use the default curve")
+	}
+	if (DecayPolicy{}).For("internal").Floor != 0.1 {
+		t.Error("Expected empty policy to fall back to step at 0.1")
+	}
+}
+
+func TestCalculateReliability_GradualDecay(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", now.Add(-10*day))
+
+	policy := DefaultPolicy()
+	policy.Decay[DefaultDecayKey] = DecayCurve{Kind: DecayLinear, WindowDays: 40}
+	calc := NewWithPolicy(db, policy)
+	calc.Now = func() time.Time { return now }
+
+	report, err := calc.CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	if math.Abs(report.FinalScore-0.75) > 1e-9 {
+		t.Errorf("Expected partially decayed score 0.75, got %f", report.FinalScore)
+	}
+	if math.Abs(report.DecayPenalty-0.25) > 1e-9 {
+		t.Errorf("Expected decay penalty 0.25, got %f", report.DecayPenalty)
+	}
+}
+
+func TestCalculator_DaysUntilBelow(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, _ = db.Exec("INSERT INTO holons (id) VALUES ('A')")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", now.Add(5*day))
+
+	policy := DefaultPolicy()
+	policy.Decay[DefaultDecayKey] = DecayCurve{Kind: DecayLinear, WindowDays: 10}
+	calc := NewWithPolicy(db, policy)
+	calc.Now = func() time.Time { return now }
+
+	// Cache today's score, then project from a graph snapshot
+	if _, err := calc.CalculateReliability(context.Background(), "A"); err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+	g, err := LoadGraph(context.Background(), db)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+
+	// Full until day 5, then loses 0.1 per day: 0.8 at day 7, below on day 8
+	days, found, err := calc.DaysUntilBelow(context.Background(), g, "A", 0.8, 30)
+	if err != nil {
+		t.Fatalf("DaysUntilBelow failed: %v", err)
+	}
+	if !found || days != 8 {
+		t.Errorf("Expected to drop below threshold in 8 days, got %d (found=%v)", days, found)
+	}
+
+	// Projected scores must not overwrite the cached (current) score
+	var cached float64
+	if err := db.QueryRow("SELECT cached_r_score FROM holons WHERE id = 'A'").Scan(&cached); err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	if cached != 1.0 {
+		t.Errorf("Expected cached score 1.0, got %f", cached)
+	}
+
+	if _, found, _ := calc.DaysUntilBelow(context.Background(), g, "A", 0.8, 5); found {
+		t.Error("Expected no crossing within a 5-day horizon")
+	}
+}
+
+func TestForecast_SharesProjectedDays(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, holon := range []string{"A", "B", "C"} {
+		_, _ = db.Exec("INSERT INTO holons (id) VALUES (?)", holon)
+		_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES (?, ?, 'pass', ?)",
+			"e"+holon, holon, now.Add(time.Duration(10*(i+1))*day))
+	}
+
+	policy := DefaultPolicy()
+	policy.Decay[DefaultDecayKey] = DecayCurve{Kind: DecayLinear, WindowDays: 10}
+	calc := NewWithPolicy(db, policy)
+	calc.Now = func() time.Time { return now }
+	g, err := LoadGraph(context.Background(), db)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+
+	// Each holon drops below 0.8 three days after its evidence expires
+	forecast := calc.NewForecast(g, 90)
+	for i, holon := range []string{"A", "B", "C"} {
+		days, found, err := forecast.DaysUntilBelow(context.Background(), holon, 0.8)
+		if err != nil {
+			t.Fatalf("DaysUntilBelow(%s) failed: %v", holon, err)
+		}
+		if want := 10*(i+1) + 3; !found || days != want {
+			t.Errorf("Expected %s to drop below threshold in %d days, got %d (found=%v)", holon, want, days, found)
+		}
+	}
+	if len(forecast.days) > 3*8 {
+		t.Errorf("Expected bisection to evaluate few days, evaluated %d", len(forecast.days))
+	}
+}
@@ -0,0 +1,174 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AggregationMode selects how evidence scores combine into a holon's self score
+type AggregationMode string
+
+const (
+	AggregateMean AggregationMode = "mean" // Weighted mean of evidence scores
+	AggregateMin  AggregationMode = "min"  // Lowest score among evidence with non-zero weight
+	AggregateBeta AggregationMode = "beta" // Posterior mean of Beta(1,1) updated with weighted successes/failures
+)
+
+// EvidencePolicy weights evidence by type, assurance level and carrier kind.
+// Keys missing from a table weigh 1.0, so unknown evidence is never silently discarded.
+type EvidencePolicy struct {
+	Aggregation AggregationMode    `json:"aggregation"`
+	Type        map[string]float64 `json:"type"`    // evidence.type (internal, research, logic, ...)
+	Level       map[string]float64 `json:"level"`   // evidence.assurance_level (L0, L1, L2)
+	Carrier     map[string]float64 `json:"carrier"` // CarrierKind of evidence.carrier_ref
+}
+
+// Carrier kinds recognised by CarrierKind
+const (
+	CarrierNone     = "none"
+	CarrierTest     = "test"
+	CarrierLogic    = "logic"
+	CarrierAudit    = "audit"
+	CarrierDoc      = "doc"
+	CarrierOpinion  = "opinion"
+	CarrierArtifact = "artifact"
+)
+
+func defaultEvidencePolicy() EvidencePolicy {
+	return EvidencePolicy{
+		Aggregation: AggregateMean,
+		Type: map[string]float64{
+			"internal":     1.0,
+			"empirical":    1.0,
+			"verification": 1.0,
+			"audit_report": 1.0,
+			"logic":        0.8,
+			"research":     0.5,
+			"external":     0.5,
+			"opinion":      0.2,
+		},
+		Level: map[string]float64{
+			"L2": 1.0,
+			"L1": 0.8,
+			"L0": 0.5,
+		},
+		Carrier: map[string]float64{
+			CarrierNone:     1.0,
+			CarrierTest:     1.0,
+			CarrierArtifact: 1.0,
+			CarrierAudit:    1.0,
+			CarrierLogic:    0.8,
+			CarrierDoc:      0.6,
+			CarrierOpinion:  0.3,
+		},
+	}
+}
+
+// CarrierKind classifies a carrier_ref: a test run, a logical argument, an audit,
+// a document link, an opinion, or some other artifact (file, commit, ...).
+func CarrierKind(ref string) string {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	switch {
+	case ref == "":
+		return CarrierNone
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"):
+		return CarrierDoc
+	case strings.Contains(ref, "opinion"), strings.Contains(ref, "chat"):
+		return CarrierOpinion
+	case strings.Contains(ref, "test"), strings.Contains(ref, "bench"):
+		return CarrierTest
+	case strings.Contains(ref, "logic"):
+		return CarrierLogic
+	case strings.Contains(ref, "audit"):
+		return CarrierAudit
+	case strings.HasSuffix(ref, ".md"), strings.HasSuffix(ref, ".pdf"), strings.HasSuffix(ref, ".txt"):
+		return CarrierDoc
+	default:
+		return CarrierArtifact
+	}
+}
+
+// Weight returns the relative weight of one piece of evidence
+func (ep EvidencePolicy) Weight(evidenceType, level, carrierRef string) float64 {
+	return lookupWeight(ep.Type, strings.ToLower(evidenceType)) *
+		lookupWeight(ep.Level, strings.ToUpper(level)) *
+		lookupWeight(ep.Carrier, CarrierKind(carrierRef))
+}
+
+func lookupWeight(table map[string]float64, key string) float64 {
+	if w, ok := table[key]; ok {
+		return w
+	}
+	return 1.0
+}
+
+func (ep EvidencePolicy) validate() error {
+	switch ep.Aggregation {
+	case AggregateMean, AggregateMin, AggregateBeta:
+	default:
+		return fmt.Errorf("unknown evidence aggregation %q (use %q, %q or %q)", ep.Aggregation, AggregateMean, AggregateMin, AggregateBeta)
+	}
+	for name, table := range map[string]map[string]float64{"type": ep.Type, "level": ep.Level, "carrier": ep.Carrier} {
+		for key, w := range table {
+			if w < 0 {
+				return fmt.Errorf("evidence %s weight for %q must not be negative", name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// weightedScore is one evidence verdict score with its policy weight
+type weightedScore struct {
+	score  float64
+	weight float64
+}
+
+// countedWeight returns the weight each of items counts with: its own weight, or 1 for every item
+// when all weights are zero. items itself is left as the caller passed it.
+func countedWeight(items []weightedScore) func(weightedScore) float64 {
+	totalWeight := 0.0
+	for _, it := range items {
+		totalWeight += it.weight
+	}
+	if totalWeight == 0 {
+		return func(weightedScore) float64 { return 1.0 }
+	}
+	return func(it weightedScore) float64 { return it.weight }
+}
+
+// Aggregate combines weighted evidence scores. If every weight is zero the
+// scores are treated as equally weighted rather than discarded.
+func (ep EvidencePolicy) Aggregate(items []weightedScore) float64 {
+	if len(items) == 0 {
+		return 0.0
+	}
+
+	weight := countedWeight(items)
+
+	switch ep.Aggregation {
+	case AggregateMin:
+		lowest := 1.0
+		for _, it := range items {
+			if weight(it) > 0 {
+				lowest = math.Min(lowest, it.score)
+			}
+		}
+		return lowest
+	case AggregateBeta:
+		alpha, beta := 1.0, 1.0
+		for _, it := range items {
+			alpha += weight(it) * it.score
+			beta += weight(it) * (1 - it.score)
+		}
+		return alpha / (alpha + beta)
+	default:
+		sum, weights := 0.0, 0.0
+		for _, it := range items {
+			sum += weight(it) * it.score
+			weights += weight(it)
+		}
+		return sum / weights
+	}
+}
@@ -0,0 +1,92 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCarrierKind(t *testing.T) {
+	tests := map[string]string{
+		"":                          CarrierNone,
+		"test-runner":               CarrierTest,
+		"internal-logic":            CarrierLogic,
+		"auditor":                   CarrierAudit,
+		"https://example.com/tests": CarrierDoc,
+		"docs/design.md":            CarrierDoc,
+		"slack opinion":             CarrierOpinion,
+		"src/payments/handler.go":   CarrierArtifact,
+	}
+	for ref, expected := range tests {
+		if got := CarrierKind(ref); got != expected {
+			t.Errorf("CarrierKind(%q) = %s, expected %s", ref, got, expected)
+		}
+	}
+}
+
+func TestEvidencePolicy_Aggregate(t *testing.T) {
+	items := []weightedScore{{score: 1.0, weight: 1.0}, {score: 0.0, weight: 0.25}}
+
+	tests := []struct {
+		mode     AggregationMode
+		expected float64
+	}{
+		{AggregateMean, 0.8},
+		{AggregateMin, 0.0},
+		{AggregateBeta, 2.0 / 3.25},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			ep := defaultEvidencePolicy()
+			ep.Aggregation = tt.mode
+			got := ep.Aggregate(append([]weightedScore{}, items...))
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Aggregate(%s) = %.4f, expected %.4f", tt.mode, got, tt.expected)
+			}
+		})
+	}
+
+	// All-zero weights fall back to an unweighted mean without touching the caller's weights
+	ep := defaultEvidencePolicy()
+	unweighted := []weightedScore{{score: 1.0}, {score: 0.0}}
+	got := ep.Aggregate(unweighted)
+	if got != 0.5 {
+		t.Errorf("Expected 0.5 for zero weights, got %.4f", got)
+	}
+	if unweighted[0].weight != 0 || unweighted[1].weight != 0 {
+		t.Errorf("Expected the weights to stay zero, got %+v", unweighted)
+	}
+}
+
+func TestCalculateReliability_WeightedEvidence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Internal benchmark passes; a research note from a blog fails
+	future := time.Now().Add(24 * time.Hour)
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, type, verdict, assurance_level, carrier_ref, valid_until) VALUES ('e1', 'A', 'internal', 'pass', 'L2', 'test-runner', ?)", future)
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, type, verdict, assurance_level, carrier_ref, valid_until) VALUES ('e2', 'A', 'research', 'fail', 'L2', 'https://blog.example.com', ?)", future)
+
+	report, err := New(db).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// Weights: e1 = 1.0, e2 = 0.5 (research) × 0.6 (doc) = 0.3 → 1.0 / 1.3
+	expected := 1.0 / 1.3
+	if math.Abs(report.SelfScore-expected) > 1e-9 {
+		t.Errorf("Expected weighted self score %.4f, got %.4f", expected, report.SelfScore)
+	}
+
+	found := false
+	for _, f := range report.Factors {
+		if strings.HasPrefix(f, "Self score: mean of 2 evidence") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected aggregation strategy in factors, got %v", report.Factors)
+	}
+}
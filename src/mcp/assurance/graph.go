@@ -0,0 +1,348 @@
+package assurance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+)
+
+// HolonRecord is the part of a holon row the calculator needs
+type HolonRecord struct {
+	Formality int
+	Scope     string
+}
+
+// EvidenceRecord is one evidence row as seen by the calculator
+type EvidenceRecord struct {
+	ID         string
+	Type       string
+	Verdict    string
+	Level      string
+	CarrierRef string
+	ValidUntil *time.Time
+	Exhausted  bool // Decayed all the way to the floor of its curve (what-if simulations)
+}
+
+// WaiverRecord is one waiver on a piece of evidence; whether it is active depends on the clock
+type WaiverRecord struct {
+	EvidenceID string
+	Until      time.Time
+}
+
+// Edge is a WLNK dependency with the congruence level of the link
+type Edge struct {
+	ID string
+	CL int
+}
+
+// GraphSource supplies holons, evidence and relations to the calculator.
+// Dependencies are the holons whose R flows into holonID: sources of componentOf
+// relations targeting it and targets of its dependsOn relations. Members are the
+// sources of memberOf relations targeting it.
+type GraphSource interface {
+	Holon(ctx context.Context, holonID string) (HolonRecord, bool, error)
+	Evidence(ctx context.Context, holonID string) ([]EvidenceRecord, error)
+	Waivers(ctx context.Context, holonID string) ([]WaiverRecord, error)
+	Dependencies(ctx context.Context, holonID string) ([]Edge, error)
+	Members(ctx context.Context, holonID string) ([]string, error)
+}
+
+// dbSource queries the database lazily, one holon at a time
+type dbSource struct {
+	db *sql.DB
+}
+
+func (s dbSource) Holon(ctx context.Context, holonID string) (HolonRecord, bool, error) {
+	var formality sql.NullInt64
+	var scope sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT formality, scope FROM holons WHERE id = ?", holonID).Scan(&formality, &scope)
+	if errors.Is(err, sql.ErrNoRows) {
+		return HolonRecord{}, false, nil
+	}
+	if err != nil {
+		return HolonRecord{}, false, err
+	}
+	return HolonRecord{Formality: int(formality.Int64), Scope: scope.String}, true, nil
+}
+
+func (s dbSource) Evidence(ctx context.Context, holonID string) ([]EvidenceRecord, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, type, verdict, assurance_level, carrier_ref, valid_until FROM evidence WHERE holon_id = ?", holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var records []EvidenceRecord
+	for rows.Next() {
+		e, err := scanEvidence(rows, nil)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, e)
+	}
+	return records, rows.Err()
+}
+
+func (s dbSource) Waivers(ctx context.Context, holonID string) ([]WaiverRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.evidence_id, w.waived_until
+		FROM waivers w JOIN evidence e ON e.id = w.evidence_id
+		WHERE e.holon_id = ?`, holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var records []WaiverRecord
+	for rows.Next() {
+		var w WaiverRecord
+		if err := rows.Scan(&w.EvidenceID, &w.Until); err != nil {
+			return nil, err
+		}
+		records = append(records, w)
+	}
+	return records, rows.Err()
+}
+
+func (s dbSource) Dependencies(ctx context.Context, holonID string) ([]Edge, error) {
+	// componentOf: Part → Whole, so the dependency is the source of rows targeting holonID
+	// dependsOn:   Dependent → Dependency, so the dependency is the target of rows from holonID
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source_id AS dep_id, COALESCE(congruence_level, 3) FROM relations
+		WHERE target_id = ? AND relation_type = 'componentOf'
+		UNION
+		SELECT target_id AS dep_id, COALESCE(congruence_level, 3) FROM relations
+		WHERE source_id = ? AND relation_type = 'dependsOn'
+		ORDER BY dep_id`, holonID, holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var edges []Edge
+	for rows.Next() {
+		var e Edge
+		if err := rows.Scan(&e.ID, &e.CL); err != nil {
+			return nil, err
+		}
+		edges = append(edges, e)
+	}
+	return edges, rows.Err()
+}
+
+func (s dbSource) Members(ctx context.Context, holonID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source_id FROM relations
+		WHERE target_id = ? AND relation_type = 'memberOf'
+		ORDER BY source_id`, holonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var members []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		members = append(members, id)
+	}
+	return members, rows.Err()
+}
+
+// Graph is an in-memory snapshot of the assurance graph, loaded with a handful of queries.
+// It implements GraphSource for batch evaluation.
+type Graph struct {
+	holons   map[string]HolonRecord
+	evidence map[string][]EvidenceRecord // by holon ID
+	waivers  map[string][]WaiverRecord   // by holon ID
+	deps     map[string][]Edge           // by dependent holon ID
+	members  map[string][]string         // by decision context ID
+}
+
+// NewGraph returns an empty Graph
+func NewGraph() *Graph {
+	return &Graph{
+		holons:   make(map[string]HolonRecord),
+		evidence: make(map[string][]EvidenceRecord),
+		waivers:  make(map[string][]WaiverRecord),
+		deps:     make(map[string][]Edge),
+		members:  make(map[string][]string),
+	}
+}
+
+// LoadGraph reads holons, evidence, waivers and WLNK relations in one pass
+func LoadGraph(ctx context.Context, db *sql.DB) (*Graph, error) {
+	g := NewGraph()
+
+	err := scanRows(ctx, db, "SELECT id, formality, scope FROM holons", func(row rowScanner) error {
+		var id string
+		var formality sql.NullInt64
+		var scope sql.NullString
+		if err := row.Scan(&id, &formality, &scope); err != nil {
+			return err
+		}
+		g.AddHolon(id, HolonRecord{Formality: int(formality.Int64), Scope: scope.String})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(ctx, db, "SELECT id, holon_id, type, verdict, assurance_level, carrier_ref, valid_until FROM evidence", func(row rowScanner) error {
+		var holonID string
+		e, err := scanEvidence(row, &holonID)
+		if err != nil {
+			return err
+		}
+		g.AddEvidence(holonID, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanRows(ctx, db, `
+		SELECT e.holon_id, w.evidence_id, w.waived_until
+		FROM waivers w JOIN evidence e ON e.id = w.evidence_id`, func(row rowScanner) error {
+		var holonID string
+		var w WaiverRecord
+		if err := row.Scan(&holonID, &w.EvidenceID, &w.Until); err != nil {
+			return err
+		}
+		g.AddWaiver(holonID, w)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A relation without a congruence level counts as CL3, as in the integrity check
+	err = scanRows(ctx, db, `
+		SELECT source_id, target_id, relation_type, COALESCE(congruence_level, 3) FROM relations
+		WHERE relation_type IN ('componentOf', 'dependsOn', 'memberOf')`, func(row rowScanner) error {
+		var source, target, relType string
+		var cl int
+		if err := row.Scan(&source, &target, &relType, &cl); err != nil {
+			return err
+		}
+		g.AddRelation(source, target, relType, cl)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// scanRows runs query and hands every row to scan, stopping at the first error
+func scanRows(ctx context.Context, db *sql.DB, query string, scan func(row rowScanner) error) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// AddHolon adds or replaces a holon
+func (g *Graph) AddHolon(id string, h HolonRecord) {
+	g.holons[id] = h
+}
+
+// AddEvidence attaches an evidence record to a holon
+func (g *Graph) AddEvidence(holonID string, e EvidenceRecord) {
+	g.evidence[holonID] = append(g.evidence[holonID], e)
+}
+
+// AddWaiver records a waiver on one of the holon's evidence
+func (g *Graph) AddWaiver(holonID string, w WaiverRecord) {
+	g.waivers[holonID] = append(g.waivers[holonID], w)
+}
+
+// AddRelation records a relation using the same directionality rules as the database source.
+// Edges stay sorted and unique so evaluation order matches the database source.
+func (g *Graph) AddRelation(source, target, relationType string, cl int) {
+	switch relationType {
+	case "componentOf":
+		g.deps[target] = insertEdge(g.deps[target], Edge{ID: source, CL: cl})
+	case "dependsOn":
+		g.deps[source] = insertEdge(g.deps[source], Edge{ID: target, CL: cl})
+	case "memberOf":
+		g.members[target] = dedupSorted(append(g.members[target], source))
+	}
+}
+
+func insertEdge(edges []Edge, e Edge) []Edge {
+	i := sort.Search(len(edges), func(i int) bool {
+		return edges[i].ID > e.ID || (edges[i].ID == e.ID && edges[i].CL >= e.CL)
+	})
+	if i < len(edges) && edges[i] == e {
+		return edges
+	}
+	edges = append(edges, Edge{})
+	copy(edges[i+1:], edges[i:])
+	edges[i] = e
+	return edges
+}
+
+// HolonIDs returns every holon in the snapshot, sorted
+func (g *Graph) HolonIDs() []string {
+	ids := make([]string, 0, len(g.holons))
+	for id := range g.holons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (g *Graph) Holon(_ context.Context, holonID string) (HolonRecord, bool, error) {
+	h, ok := g.holons[holonID]
+	return h, ok, nil
+}
+
+func (g *Graph) Evidence(_ context.Context, holonID string) ([]EvidenceRecord, error) {
+	return g.evidence[holonID], nil
+}
+
+func (g *Graph) Waivers(_ context.Context, holonID string) ([]WaiverRecord, error) {
+	return g.waivers[holonID], nil
+}
+
+func (g *Graph) Dependencies(_ context.Context, holonID string) ([]Edge, error) {
+	return g.deps[holonID], nil
+}
+
+func (g *Graph) Members(_ context.Context, holonID string) ([]string, error) {
+	return g.members[holonID], nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEvidence reads an evidence row; holonID is scanned first when non-nil
+func scanEvidence(rows rowScanner, holonID *string) (EvidenceRecord, error) {
+	var e EvidenceRecord
+	var level, carrierRef sql.NullString
+	dest := []interface{}{&e.ID, &e.Type, &e.Verdict, &level, &carrierRef, &e.ValidUntil}
+	if holonID != nil {
+		dest = append([]interface{}{&e.ID, holonID}, dest[1:]...)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return EvidenceRecord{}, err
+	}
+	e.Level = level.String
+	e.CarrierRef = carrierRef.String
+	return e, nil
+}
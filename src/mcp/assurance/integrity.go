@@ -0,0 +1,221 @@
+package assurance
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+)
+
+// DanglingRelation is a relation whose endpoint does not exist
+type DanglingRelation struct {
+	SourceID     string
+	TargetID     string
+	RelationType string
+	Missing      string // "source", "target" or "both"
+}
+
+// OrphanedEvidence is evidence attached to a holon that does not exist
+type OrphanedEvidence struct {
+	EvidenceID string
+	HolonID    string
+}
+
+// GraphCheck is the result of a graph integrity check
+type GraphCheck struct {
+	Holons    int
+	Relations int
+	Evidence  int
+
+	Cycles            [][]string // Strongly connected components of the evaluation graph (sorted IDs)
+	DanglingRelations []DanglingRelation
+	OrphanedEvidence  []OrphanedEvidence
+}
+
+// OK reports whether no integrity problem was found
+func (gc *GraphCheck) OK() bool {
+	return len(gc.Cycles) == 0 && len(gc.DanglingRelations) == 0 && len(gc.OrphanedEvidence) == 0
+}
+
+// CheckGraph inspects the knowledge graph for cycles in the relations the calculator
+// follows (componentOf, dependsOn, memberOf), relations pointing at missing holons and
+// evidence attached to missing holons. verifiedBy relations start at an evidence ID.
+func CheckGraph(ctx context.Context, db *sql.DB) (*GraphCheck, error) {
+	check := &GraphCheck{}
+
+	holons, err := queryIDs(ctx, db, "SELECT id FROM holons")
+	if err != nil {
+		return nil, err
+	}
+	check.Holons = len(holons)
+
+	evidenceIDs := make(map[string]bool)
+	rows, err := db.QueryContext(ctx, "SELECT id, holon_id FROM evidence ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id, holonID string
+		if err := rows.Scan(&id, &holonID); err != nil {
+			continue
+		}
+		evidenceIDs[id] = true
+		if !holons[holonID] {
+			check.OrphanedEvidence = append(check.OrphanedEvidence, OrphanedEvidence{EvidenceID: id, HolonID: holonID})
+		}
+	}
+	_ = rows.Close()
+	check.Evidence = len(evidenceIDs)
+
+	g := NewGraph()
+	rows, err = db.QueryContext(ctx, "SELECT source_id, target_id, relation_type, COALESCE(congruence_level, 3) FROM relations ORDER BY source_id, target_id, relation_type")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var source, target, relType string
+		var cl int
+		if err := rows.Scan(&source, &target, &relType, &cl); err != nil {
+			continue
+		}
+		check.Relations++
+
+		sourceOK := holons[source]
+		if relType == "verifiedBy" {
+			sourceOK = evidenceIDs[source]
+		}
+		targetOK := holons[target]
+		switch {
+		case !sourceOK && !targetOK:
+			check.DanglingRelations = append(check.DanglingRelations, DanglingRelation{source, target, relType, "both"})
+		case !sourceOK:
+			check.DanglingRelations = append(check.DanglingRelations, DanglingRelation{source, target, relType, "source"})
+		case !targetOK:
+			check.DanglingRelations = append(check.DanglingRelations, DanglingRelation{source, target, relType, "target"})
+		}
+
+		g.AddRelation(source, target, relType, cl)
+	}
+	_ = rows.Close()
+
+	check.Cycles = g.StronglyConnected()
+	return check, nil
+}
+
+// StronglyConnected returns the cycles of the evaluation graph (each holon points at its
+// dependencies and members) as strongly connected components with more than one holon,
+// or a single holon that depends on itself. Uses Tarjan's algorithm.
+func (g *Graph) StronglyConnected() [][]string {
+	successors := func(id string) []string {
+		var out []string
+		for _, e := range g.deps[id] {
+			out = append(out, e.ID)
+		}
+		return append(out, g.members[id]...)
+	}
+
+	nodes := make(map[string]bool)
+	for id := range g.holons {
+		nodes[id] = true
+	}
+	for id := range g.deps {
+		nodes[id] = true
+		for _, next := range successors(id) {
+			nodes[next] = true
+		}
+	}
+	for id := range g.members {
+		nodes[id] = true
+		for _, next := range successors(id) {
+			nodes[next] = true
+		}
+	}
+	ordered := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ordered = append(ordered, id)
+	}
+	sort.Strings(ordered)
+
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range successors(v) {
+			if _, seen := indices[w]; !seen {
+				strongConnect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], indices[w])
+			}
+		}
+
+		if lowlink[v] != indices[v] {
+			return
+		}
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 || g.hasSelfLoop(v) {
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, v := range ordered {
+		if _, seen := indices[v]; !seen {
+			strongConnect(v)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+func (g *Graph) hasSelfLoop(id string) bool {
+	for _, e := range g.deps[id] {
+		if e.ID == id {
+			return true
+		}
+	}
+	for _, m := range g.members[id] {
+		if m == id {
+			return true
+		}
+	}
+	return false
+}
+
+func queryIDs(ctx context.Context, db *sql.DB, query string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			continue
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,58 @@
+package assurance
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGraph_StronglyConnected(t *testing.T) {
+	g := NewGraph()
+	// A → B → C → A is a cycle; D → A hangs off it; E depends on itself
+	g.AddRelation("B", "A", "componentOf", 3)
+	g.AddRelation("B", "C", "dependsOn", 3)
+	g.AddRelation("A", "C", "componentOf", 3)
+	g.AddRelation("D", "A", "dependsOn", 3)
+	g.AddRelation("E", "E", "dependsOn", 3)
+
+	got := g.StronglyConnected()
+	expected := [][]string{{"A", "B", "C"}, {"E"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("StronglyConnected() = %v, expected %v", got, expected)
+	}
+}
+
+func TestCheckGraph(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	for _, id := range []string{"A", "B"} {
+		_, _ = db.Exec("INSERT INTO holons (id) VALUES (?)", id)
+	}
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict) VALUES ('e1', 'A', 'pass')")
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict) VALUES ('e-orphan', 'ghost', 'pass')")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'B', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('B', 'A', 'dependsOn', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('e1', 'A', 'verifiedBy', 3)")
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'missing', 'componentOf', 3)")
+
+	check, err := CheckGraph(context.Background(), db)
+	if err != nil {
+		t.Fatalf("CheckGraph failed: %v", err)
+	}
+	if check.OK() {
+		t.Fatal("Expected integrity problems")
+	}
+
+	if !reflect.DeepEqual(check.Cycles, [][]string{{"A", "B"}}) {
+		t.Errorf("Expected cycle [A B], got %v", check.Cycles)
+	}
+	// verifiedBy starts at an evidence ID, so e1 → A is valid
+	expectedDangling := []DanglingRelation{{SourceID: "A", TargetID: "missing", RelationType: "componentOf", Missing: "target"}}
+	if !reflect.DeepEqual(check.DanglingRelations, expectedDangling) {
+		t.Errorf("Expected dangling %v, got %v", expectedDangling, check.DanglingRelations)
+	}
+	if len(check.OrphanedEvidence) != 1 || check.OrphanedEvidence[0].EvidenceID != "e-orphan" {
+		t.Errorf("Expected orphaned e-orphan, got %v", check.OrphanedEvidence)
+	}
+}
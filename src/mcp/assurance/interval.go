@@ -0,0 +1,121 @@
+package assurance
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultCredibility is the probability mass of the reported credible interval
+const DefaultCredibility = 0.9
+
+// Interval is a credible interval around a reliability score
+type Interval struct {
+	Lower float64
+	Upper float64
+}
+
+func (iv Interval) String() string {
+	return fmt.Sprintf("[%.2f, %.2f]", iv.Lower, iv.Upper)
+}
+
+// Width is Upper - Lower: how much the evidence leaves undecided
+func (iv Interval) Width() float64 {
+	return iv.Upper - iv.Lower
+}
+
+// CredibleInterval treats evidence as weighted PASS/FAIL counts (a DEGRADE is half of each)
+// and returns the equal-tailed interval of Beta(1 + successes, 1 + failures) holding
+// the given probability mass. If every weight is zero each item counts once, as in Aggregate.
+// The interval is widened to contain point, so it always brackets the score produced by the
+// configured aggregation.
+func CredibleInterval(items []weightedScore, credibility, point float64) Interval {
+	if len(items) == 0 {
+		// L0: no evidence is scored as 0 and there is nothing to be uncertain about
+		return Interval{Lower: point, Upper: point}
+	}
+
+	weight := countedWeight(items)
+	alpha, beta := 1.0, 1.0
+	for _, it := range items {
+		alpha += weight(it) * it.score
+		beta += weight(it) * (1 - it.score)
+	}
+	tail := (1 - credibility) / 2
+	iv := Interval{
+		Lower: betaQuantile(tail, alpha, beta),
+		Upper: betaQuantile(1-tail, alpha, beta),
+	}
+	iv.Lower = math.Min(iv.Lower, point)
+	iv.Upper = math.Max(iv.Upper, point)
+	return iv
+}
+
+// betaQuantile inverts the regularized incomplete beta function by bisection
+func betaQuantile(p, a, b float64) float64 {
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 60; i++ {
+		mid := (lo + hi) / 2
+		if regularizedBeta(mid, a, b) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularizedBeta computes I_x(a, b) with the continued fraction expansion (Lentz's method)
+func regularizedBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly only below the mean; use the symmetry otherwise
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(1-x, b, a)/b
+	}
+	return front * betaContinuedFraction(x, a, b) / a
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+		tiny          = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		for _, num := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,69 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCredibleInterval_SinglePass(t *testing.T) {
+	// Beta(2, 1): CDF is x², so the 5% quantile is sqrt(0.05)
+	iv := CredibleInterval([]weightedScore{{score: 1, weight: 1}}, 0.9, 1.0)
+	if math.Abs(iv.Lower-math.Sqrt(0.05)) > 1e-6 {
+		t.Errorf("Expected lower bound %.4f, got %.4f", math.Sqrt(0.05), iv.Lower)
+	}
+	if iv.Upper != 1.0 {
+		t.Errorf("Expected upper bound widened to the point estimate 1.0, got %.4f", iv.Upper)
+	}
+
+	// Zero-weight evidence counts once, as in Aggregate
+	if unweighted := CredibleInterval([]weightedScore{{score: 1}}, 0.9, 1.0); unweighted != iv {
+		t.Errorf("Expected zero-weight evidence to count once, got %s instead of %s", unweighted, iv)
+	}
+}
+
+func TestCredibleInterval_NarrowsWithEvidence(t *testing.T) {
+	few := []weightedScore{{1, 1}, {1, 1}}
+	many := make([]weightedScore, 20)
+	for i := range many {
+		many[i] = weightedScore{score: 1, weight: 1}
+	}
+	if CredibleInterval(many, 0.9, 1).Width() >= CredibleInterval(few, 0.9, 1).Width() {
+		t.Error("Expected more evidence to narrow the interval")
+	}
+
+	none := CredibleInterval(nil, 0.9, 0)
+	if none.Lower != 0 || none.Upper != 0 {
+		t.Errorf("Expected [0, 0] without evidence, got %s", none)
+	}
+}
+
+func TestCalculateReliability_IntervalPropagates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	future := time.Now().Add(24 * time.Hour)
+	_, _ = db.Exec("INSERT INTO holons (id) VALUES ('A'), ('B')")
+	for i, id := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES (?, 'A', 'pass', ?)", id, future.Add(time.Duration(i)*time.Minute))
+	}
+	_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('b1', 'B', 'pass', ?)", future)
+	_, _ = db.Exec("INSERT INTO relations (source_id, target_id, relation_type, congruence_level) VALUES ('A', 'B', 'dependsOn', 2)")
+
+	report, err := New(db).CalculateReliability(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("CalculateReliability failed: %v", err)
+	}
+
+	// A is well evidenced, but B's single PASS (CL2) dominates the lower bound
+	if report.Interval.Lower >= report.SelfInterval.Lower {
+		t.Errorf("Expected the dependency to pull the lower bound down: self %s, effective %s", report.SelfInterval, report.Interval)
+	}
+	if math.Abs(report.Interval.Lower-(math.Sqrt(0.05)-0.1)) > 1e-6 {
+		t.Errorf("Expected lower bound sqrt(0.05) - Φ(CL2), got %.4f", report.Interval.Lower)
+	}
+	if report.FinalScore < report.Interval.Lower || report.FinalScore > report.Interval.Upper {
+		t.Errorf("FinalScore %.2f outside interval %s", report.FinalScore, report.Interval)
+	}
+}
@@ -0,0 +1,196 @@
+package assurance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PolicyFile is the assurance policy location, relative to the .quint directory
+const PolicyFile = "assurance.json"
+
+// DefaultPolicyVersion identifies the built-in policy in reports
+const DefaultPolicyVersion = "builtin-v4"
+
+// PenaltyMode selects how the congruence penalty Φ(CL) is applied to a dependency's R
+type PenaltyMode string
+
+const (
+	PenaltySubtractive    PenaltyMode = "subtractive"    // R_eff = max(0, R - Φ(CL))
+	PenaltyMultiplicative PenaltyMode = "multiplicative" // R_eff = R × (1 - Φ(CL))
+)
+
+// GateBound selects which estimate of R_eff is compared with the assurance threshold
+type GateBound string
+
+const (
+	GatePoint GateBound = "point" // FinalScore
+	GateLower GateBound = "lower" // Lower end of the credible interval
+)
+
+// Policy holds the tunable parameters of the assurance calculus.
+// Every AssuranceReport records the Version that produced it.
+type Policy struct {
+	Version  string         `json:"version"`
+	Penalty  PenaltyPolicy  `json:"penalty"`
+	Evidence EvidencePolicy `json:"evidence"`
+	Decay    DecayPolicy    `json:"decay"`
+
+	// CyclePenalty is subtracted from the weakest score of a dependency cycle where its members depend on each other
+	CyclePenalty float64 `json:"cycle_penalty"`
+
+	// Credibility is the probability mass of the credible interval reported around R
+	Credibility float64 `json:"credibility"`
+
+	// Gate selects the estimate the FSM compares with the assurance threshold
+	Gate GateBound `json:"gate"`
+}
+
+// PenaltyPolicy is the table-driven congruence penalty function Φ(CL)
+type PenaltyPolicy struct {
+	Mode PenaltyMode     `json:"mode"`
+	CL   map[int]float64 `json:"cl"` // Φ for CL0..CL3, each in [0, 1]
+}
+
+// DefaultPolicy returns the built-in policy: subtractive Φ (CL3=0.0, CL2=0.1, CL1=0.4, CL0=0.9)
+// a weighted mean of evidence scores, step decay to 0.1 at valid_until, a 0.5 cycle penalty,
+// 90% credible intervals and a threshold gate on the point estimate.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Version: DefaultPolicyVersion,
+		Penalty: PenaltyPolicy{
+			Mode: PenaltySubtractive,
+			CL:   map[int]float64{0: 0.9, 1: 0.4, 2: 0.1, 3: 0.0},
+		},
+		Evidence: defaultEvidencePolicy(),
+		Decay:    defaultDecayPolicy(),
+
+		CyclePenalty: 0.5,
+		Credibility:  DefaultCredibility,
+		Gate:         GatePoint,
+	}
+}
+
+// LoadPolicy reads <quintDir>/assurance.json on top of the defaults.
+// A missing file yields DefaultPolicy. A file without a "version" gets one derived
+// from its content hash, so scores stay traceable to the exact configuration.
+func LoadPolicy(quintDir string) (*Policy, error) {
+	path := filepath.Join(quintDir, PolicyFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultPolicy(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy decodes a JSON policy on top of DefaultPolicy and validates it
+func ParsePolicy(data []byte) (*Policy, error) {
+	p := DefaultPolicy()
+	p.Version = ""
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("invalid assurance policy: %w", err)
+	}
+	p.normalize()
+	if p.Version == "" {
+		hash := sha256.Sum256(data)
+		p.Version = "custom-" + hex.EncodeToString(hash[:4])
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid assurance policy %s: %w", p.Version, err)
+	}
+	return p, nil
+}
+
+// normalize rewrites the keys of the lookup tables to the case they are looked up in
+// (evidence types and decay keys lower case, levels upper case), so "Empirical" in a policy file
+// configures evidence of type "empirical". A key written by the user wins over the built-in one.
+func (p *Policy) normalize() {
+	normalizeKeys(p.Decay, strings.ToLower)
+	normalizeKeys(p.Evidence.Type, strings.ToLower)
+	normalizeKeys(p.Evidence.Level, strings.ToUpper)
+	normalizeKeys(p.Evidence.Carrier, strings.ToLower)
+}
+
+func normalizeKeys[V any](table map[string]V, canonical func(string) string) {
+	var keys []string
+	for key := range table {
+		if canonical(key) != key {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		table[canonical(key)] = table[key]
+		delete(table, key)
+	}
+}
+
+// Validate checks that the policy is complete, Φ is monotone (higher CL never costs more)
+// evidence weights are non-negative, decay curves are well-formed and the interval settings are sane
+func (p *Policy) Validate() error {
+	switch p.Penalty.Mode {
+	case PenaltySubtractive, PenaltyMultiplicative:
+	default:
+		return fmt.Errorf("unknown penalty mode %q (use %q or %q)", p.Penalty.Mode, PenaltySubtractive, PenaltyMultiplicative)
+	}
+
+	prev := math.Inf(1)
+	for cl := 0; cl <= 3; cl++ {
+		phi, ok := p.Penalty.CL[cl]
+		if !ok {
+			return fmt.Errorf("penalty for CL%d is missing", cl)
+		}
+		if phi < 0 || phi > 1 {
+			return fmt.Errorf("penalty for CL%d must be within [0, 1], got %.2f", cl, phi)
+		}
+		if phi > prev {
+			return fmt.Errorf("penalty must not increase with CL: CL%d=%.2f > CL%d=%.2f", cl, phi, cl-1, prev)
+		}
+		prev = phi
+	}
+	if p.CyclePenalty < 0 || p.CyclePenalty > 1 {
+		return fmt.Errorf("cycle_penalty must be within [0, 1], got %.2f", p.CyclePenalty)
+	}
+	if p.Credibility <= 0 || p.Credibility >= 1 {
+		return fmt.Errorf("credibility must be within (0, 1), got %.2f", p.Credibility)
+	}
+	switch p.Gate {
+	case GatePoint, GateLower:
+	default:
+		return fmt.Errorf("unknown gate %q (use %q or %q)", p.Gate, GatePoint, GateLower)
+	}
+	if err := p.Evidence.validate(); err != nil {
+		return err
+	}
+	return p.Decay.validate()
+}
+
+// Phi returns Φ(CL). Levels outside 0..3 are clamped.
+func (pp PenaltyPolicy) Phi(cl int) float64 {
+	if cl < 0 {
+		cl = 0
+	}
+	if cl > 3 {
+		cl = 3
+	}
+	return pp.CL[cl]
+}
+
+// Apply returns the dependency score after the congruence penalty for cl
+func (pp PenaltyPolicy) Apply(r float64, cl int) float64 {
+	phi := pp.Phi(cl)
+	if pp.Mode == PenaltyMultiplicative {
+		return r * (1 - phi)
+	}
+	return math.Max(0, r-phi)
+}
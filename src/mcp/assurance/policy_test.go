@@ -0,0 +1,118 @@
+package assurance
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPolicy_MissingFileIsDefault(t *testing.T) {
+	p, err := LoadPolicy(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if p.Version != DefaultPolicyVersion {
+		t.Errorf("Expected version %s, got %s", DefaultPolicyVersion, p.Version)
+	}
+	if p.Penalty.Mode != PenaltySubtractive {
+		t.Errorf("Expected subtractive mode, got %s", p.Penalty.Mode)
+	}
+}
+
+func TestLoadPolicy_File(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"version": "team-2026", "penalty": {"mode": "multiplicative", "cl": {"0": 0.5}}}`
+	if err := os.WriteFile(filepath.Join(dir, PolicyFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := LoadPolicy(dir)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if p.Version != "team-2026" {
+		t.Errorf("Expected version team-2026, got %s", p.Version)
+	}
+	if p.Penalty.Phi(0) != 0.5 {
+		t.Errorf("Expected Φ(0)=0.5, got %.2f", p.Penalty.Phi(0))
+	}
+	if p.Penalty.Phi(1) != 0.4 {
+		t.Errorf("Expected unset levels to keep defaults, Φ(1)=%.2f", p.Penalty.Phi(1))
+	}
+}
+
+func TestParsePolicy_VersionFromContent(t *testing.T) {
+	p, err := ParsePolicy([]byte(`{"penalty": {"mode": "multiplicative"}}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy failed: %v", err)
+	}
+	if !strings.HasPrefix(p.Version, "custom-") {
+		t.Errorf("Expected content-derived version, got %s", p.Version)
+	}
+}
+
+func TestParsePolicy_NormalizesKeys(t *testing.T) {
+	p, err := ParsePolicy([]byte(`{
+		"decay": {"Empirical": {"curve": "linear", "window_days": 30}},
+		"evidence": {"type": {"Research": 0.5}, "level": {"l1": 0.3}}
+	}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy failed: %v", err)
+	}
+	if p.Decay.For("empirical").Kind != DecayLinear {
+		t.Errorf("Expected the Empirical curve to apply to empirical evidence, got %s", p.Decay.For("empirical").Kind)
+	}
+	if w := p.Evidence.Weight("research", "L1", CarrierNone); math.Abs(w-0.15) > 1e-9 {
+		t.Errorf("Expected weight 0.5*0.3=0.15, got %.2f", w)
+	}
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown mode":    `{"penalty": {"mode": "exponential"}}`,
+		"out of range":    `{"penalty": {"cl": {"0": 1.5}}}`,
+		"not monotone":    `{"penalty": {"cl": {"3": 0.5}}}`,
+		"malformed json":  `{"penalty":`,
+		"bad aggregation": `{"evidence": {"aggregation": "median"}}`,
+		"negative weight": `{"evidence": {"type": {"research": -1}}}`,
+		"credibility 1":   `{"credibility": 1}`,
+		"unknown gate":    `{"gate": "upper"}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePolicy([]byte(content)); err == nil {
+				t.Errorf("Expected error for %s", content)
+			}
+		})
+	}
+}
+
+func TestPenaltyPolicy_Apply(t *testing.T) {
+	sub := DefaultPolicy().Penalty
+	mul := DefaultPolicy().Penalty
+	mul.Mode = PenaltyMultiplicative
+
+	tests := []struct {
+		name     string
+		pp       PenaltyPolicy
+		r        float64
+		cl       int
+		expected float64
+	}{
+		{"subtractive CL1", sub, 0.5, 1, 0.1},
+		{"subtractive floors at zero", sub, 0.5, 0, 0.0},
+		{"multiplicative CL1", mul, 0.5, 1, 0.3},
+		{"multiplicative CL0", mul, 0.5, 0, 0.05},
+		{"CL3 is free", mul, 0.5, 3, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.pp.Apply(tt.r, tt.cl)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Apply(%.2f, CL%d) = %.4f, expected %.4f", tt.r, tt.cl, got, tt.expected)
+			}
+		})
+	}
+}
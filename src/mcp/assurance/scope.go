@@ -0,0 +1,245 @@
+package assurance
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClaimScope is the structured scope (G) of a claim: the context slices it applies to.
+// Each dimension (service, env, data_volume, ...) lists its admissible values.
+// A dimension absent from the map is unconstrained, so an empty ClaimScope is unbounded.
+// A dimension with no values makes the scope empty: the claim applies nowhere.
+type ClaimScope map[string][]string
+
+// unboundedScopeText lists the free-text spellings treated as "applies everywhere"
+var unboundedScopeText = map[string]bool{
+	"":          true,
+	"*":         true,
+	"any":       true,
+	"all":       true,
+	"global":    true,
+	"unbounded": true,
+}
+
+// ParseScope parses the holons.scope text into a ClaimScope.
+// Accepted forms:
+//   - "global" (or empty, "*", "any") for an unbounded scope
+//   - "service=payments,checkout; env=prod" (dimensions separated by ';', values by ',')
+//   - a JSON object: {"service": ["payments"], "env": ["prod"]}
+//
+// Dimension names and values are case-insensitive. Free text that matches none
+// of these forms returns an error so callers can decide how to treat legacy scopes.
+func ParseScope(text string) (ClaimScope, error) {
+	text = strings.TrimSpace(text)
+	if unboundedScopeText[strings.ToLower(text)] {
+		return ClaimScope{}, nil
+	}
+
+	if strings.HasPrefix(text, "{") {
+		var raw map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON scope: %w", err)
+		}
+		g := ClaimScope{}
+		for dim, v := range raw {
+			switch val := v.(type) {
+			case string:
+				g.add(dim, val)
+			case []interface{}:
+				g.ensure(dim)
+				for _, item := range val {
+					s, ok := item.(string)
+					if !ok {
+						return nil, fmt.Errorf("scope dimension %q: values must be strings", dim)
+					}
+					g.add(dim, s)
+				}
+			default:
+				return nil, fmt.Errorf("scope dimension %q: expected string or array of strings", dim)
+			}
+		}
+		return g.normalize(), nil
+	}
+
+	g := ClaimScope{}
+	for _, part := range strings.Split(text, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		dim, values, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(dim) == "" {
+			return nil, fmt.Errorf("not a structured scope: %q (expected dimension=value[,value])", part)
+		}
+		g.ensure(dim)
+		for _, v := range strings.Split(values, ",") {
+			g.add(dim, v)
+		}
+	}
+	return g.normalize(), nil
+}
+
+func (g ClaimScope) ensure(dim string) {
+	dim = strings.ToLower(strings.TrimSpace(dim))
+	if _, ok := g[dim]; !ok {
+		g[dim] = []string{}
+	}
+}
+
+func (g ClaimScope) add(dim, value string) {
+	dim = strings.ToLower(strings.TrimSpace(dim))
+	value = strings.ToLower(strings.TrimSpace(value))
+	g.ensure(dim)
+	if value != "" {
+		g[dim] = append(g[dim], value)
+	}
+}
+
+// normalize sorts and de-duplicates values so String() is canonical
+func (g ClaimScope) normalize() ClaimScope {
+	for dim, values := range g {
+		g[dim] = dedupSorted(values)
+	}
+	return g
+}
+
+// IsUnbounded reports whether the scope constrains no dimension
+func (g ClaimScope) IsUnbounded() bool {
+	return len(g) == 0
+}
+
+// IsEmpty reports whether some dimension admits no value, i.e. the claim applies nowhere
+func (g ClaimScope) IsEmpty() bool {
+	for _, values := range g {
+		if len(values) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersect returns the slices admitted by both scopes (serial composition, e.g. dependsOn).
+func (g ClaimScope) Intersect(other ClaimScope) ClaimScope {
+	out := ClaimScope{}
+	for dim, values := range g {
+		out[dim] = append([]string{}, values...)
+	}
+	for dim, values := range other {
+		existing, ok := out[dim]
+		if !ok {
+			out[dim] = append([]string{}, values...)
+			continue
+		}
+		keep := make(map[string]bool, len(values))
+		for _, v := range values {
+			keep[v] = true
+		}
+		common := []string{}
+		for _, v := range existing {
+			if keep[v] {
+				common = append(common, v)
+			}
+		}
+		out[dim] = common
+	}
+	return out.normalize()
+}
+
+// Union returns the slices admitted by either scope (parallel alternatives, e.g. memberOf).
+// A dimension constrained by only one side becomes unconstrained.
+func (g ClaimScope) Union(other ClaimScope) ClaimScope {
+	if g.IsEmpty() {
+		return other.clone()
+	}
+	if other.IsEmpty() {
+		return g.clone()
+	}
+	out := ClaimScope{}
+	for dim, values := range g {
+		otherValues, ok := other[dim]
+		if !ok {
+			continue
+		}
+		out[dim] = append(append([]string{}, values...), otherValues...)
+	}
+	return out.normalize()
+}
+
+func (g ClaimScope) clone() ClaimScope {
+	out := ClaimScope{}
+	for dim, values := range g {
+		out[dim] = append([]string{}, values...)
+	}
+	return out
+}
+
+// String renders the canonical text form, e.g. "env=prod,staging; service=payments"
+func (g ClaimScope) String() string {
+	if g.IsUnbounded() {
+		return "global"
+	}
+	dims := make([]string, 0, len(g))
+	for dim := range g {
+		dims = append(dims, dim)
+	}
+	sort.Strings(dims)
+
+	parts := make([]string, 0, len(dims))
+	for _, dim := range dims {
+		parts = append(parts, dim+"="+strings.Join(g[dim], ","))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ScopeCheck is the outcome of testing a context slice against a claim scope
+type ScopeCheck struct {
+	Applies     bool     // No constrained dimension rejects the slice
+	Mismatches  []string // "dim=value" pairs outside the scope
+	Unspecified []string // Dimensions the scope constrains but the slice leaves open
+}
+
+// Check tests whether the slice lies within the scope. Dimensions the slice does
+// not mention are reported as Unspecified rather than treated as a mismatch.
+func (g ClaimScope) Check(slice ClaimScope) ScopeCheck {
+	result := ScopeCheck{Applies: true}
+
+	dims := make([]string, 0, len(g))
+	for dim := range g {
+		dims = append(dims, dim)
+	}
+	sort.Strings(dims)
+
+	for _, dim := range dims {
+		wanted, ok := slice[dim]
+		if !ok || len(wanted) == 0 {
+			result.Unspecified = append(result.Unspecified, dim)
+			continue
+		}
+		admitted := make(map[string]bool, len(g[dim]))
+		for _, v := range g[dim] {
+			admitted[v] = true
+		}
+		for _, v := range wanted {
+			if !admitted[v] {
+				result.Applies = false
+				result.Mismatches = append(result.Mismatches, dim+"="+v)
+			}
+		}
+	}
+	return result
+}
+
+func dedupSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := []string{}
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
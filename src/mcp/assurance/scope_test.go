@@ -0,0 +1,85 @@
+package assurance
+
+import (
+	"testing"
+)
+
+func mustParseScope(t *testing.T, text string) ClaimScope {
+	t.Helper()
+	g, err := ParseScope(text)
+	if err != nil {
+		t.Fatalf("ParseScope(%q) failed: %v", text, err)
+	}
+	return g
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "global"},
+		{"global", "global"},
+		{"service=payments", "service=payments"},
+		{"Env=Prod, staging; service=payments", "env=prod,staging; service=payments"},
+		{`{"service": ["checkout", "payments"], "env": "prod"}`, "env=prod; service=checkout,payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			g := mustParseScope(t, tt.input)
+			if g.String() != tt.expected {
+				t.Errorf("ParseScope(%q).String() = %q, expected %q", tt.input, g.String(), tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParseScope("backend services"); err == nil {
+		t.Error("Expected error for free-text scope")
+	}
+}
+
+func TestClaimScope_IntersectAndUnion(t *testing.T) {
+	a := mustParseScope(t, "service=payments,checkout; env=prod")
+	b := mustParseScope(t, "service=payments; region=eu")
+
+	intersection := a.Intersect(b)
+	if got := intersection.String(); got != "env=prod; region=eu; service=payments" {
+		t.Errorf("Intersect = %q", got)
+	}
+
+	union := a.Union(b)
+	if got := union.String(); got != "service=checkout,payments" {
+		t.Errorf("Union = %q (dimensions constrained on one side only should be dropped)", got)
+	}
+
+	disjoint := mustParseScope(t, "env=prod").Intersect(mustParseScope(t, "env=dev"))
+	if !disjoint.IsEmpty() {
+		t.Errorf("Expected empty scope for disjoint intersection, got %q", disjoint.String())
+	}
+
+	if got := disjoint.Union(b).String(); got != b.String() {
+		t.Errorf("Union with empty scope should return the other side, got %q", got)
+	}
+}
+
+func TestClaimScope_Check(t *testing.T) {
+	g := mustParseScope(t, "service=payments; env=prod,staging")
+
+	check := g.Check(mustParseScope(t, "service=payments; env=prod"))
+	if !check.Applies || len(check.Mismatches) != 0 || len(check.Unspecified) != 0 {
+		t.Errorf("Expected full match, got %+v", check)
+	}
+
+	check = g.Check(mustParseScope(t, "service=billing"))
+	if check.Applies {
+		t.Errorf("Expected mismatch for service=billing, got %+v", check)
+	}
+	if len(check.Unspecified) != 1 || check.Unspecified[0] != "env" {
+		t.Errorf("Expected env to be unspecified, got %+v", check.Unspecified)
+	}
+
+	if !(ClaimScope{}).Check(mustParseScope(t, "env=dev")).Applies {
+		t.Error("Unbounded scope should apply to any slice")
+	}
+}
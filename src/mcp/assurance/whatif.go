@@ -0,0 +1,218 @@
+package assurance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// What-if change operations
+const (
+	ChangeAddEvidence    = "add_evidence"    // Attach a new evidence to HolonID (verdict defaults to pass)
+	ChangeSetCL          = "set_cl"          // Set the CL of the edge HolonID → DependencyID
+	ChangeExpireEvidence = "expire_evidence" // Make EvidenceID expire and decay to its floor
+)
+
+// Change is one hypothetical modification of the graph
+type Change struct {
+	Op           string `json:"op"`
+	HolonID      string `json:"holon_id,omitempty"`
+	DependencyID string `json:"dependency_id,omitempty"`
+	CL           int    `json:"cl,omitempty"`
+	EvidenceID   string `json:"evidence_id,omitempty"`
+	Verdict      string `json:"verdict,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Level        string `json:"level,omitempty"`
+}
+
+func (ch Change) String() string {
+	switch ch.Op {
+	case ChangeAddEvidence:
+		verdict := ch.Verdict
+		if verdict == "" {
+			verdict = "pass"
+		}
+		return fmt.Sprintf("add %s evidence to %s", strings.ToUpper(verdict), ch.HolonID)
+	case ChangeSetCL:
+		return fmt.Sprintf("set CL of %s → %s to CL%d", ch.HolonID, ch.DependencyID, ch.CL)
+	case ChangeExpireEvidence:
+		return fmt.Sprintf("expire evidence %s", ch.EvidenceID)
+	default:
+		return ch.Op
+	}
+}
+
+// WhatIfResult compares a holon's assurance before and after hypothetical changes
+type WhatIfResult struct {
+	HolonID string
+	Changes []Change
+	Before  *AssuranceReport
+	After   *AssuranceReport
+	Delta   float64 // After.FinalScore - Before.FinalScore
+}
+
+// Clone returns a deep copy of the graph, safe to modify for simulations
+func (g *Graph) Clone() *Graph {
+	out := NewGraph()
+	for id, h := range g.holons {
+		out.holons[id] = h
+	}
+	for id, records := range g.evidence {
+		out.evidence[id] = append([]EvidenceRecord{}, records...)
+	}
+	for id, records := range g.waivers {
+		out.waivers[id] = append([]WaiverRecord{}, records...)
+	}
+	for id, edges := range g.deps {
+		out.deps[id] = append([]Edge{}, edges...)
+	}
+	for id, members := range g.members {
+		out.members[id] = append([]string{}, members...)
+	}
+	return out
+}
+
+// Apply performs a hypothetical change on the graph; now is the simulation clock
+func (g *Graph) Apply(ch Change, now time.Time) error {
+	switch ch.Op {
+	case ChangeAddEvidence:
+		if _, ok := g.holons[ch.HolonID]; !ok {
+			return fmt.Errorf("%s: holon %q not found", ch.Op, ch.HolonID)
+		}
+		verdict := strings.ToLower(ch.Verdict)
+		switch verdict {
+		case "":
+			verdict = "pass"
+		case "pass", "degrade", "fail":
+		default:
+			return fmt.Errorf("%s: verdict must be pass, degrade or fail, got %q", ch.Op, ch.Verdict)
+		}
+		evidenceType, level := ch.Type, ch.Level
+		if evidenceType == "" {
+			evidenceType = "internal"
+		}
+		if level == "" {
+			level = "L2"
+		}
+		g.AddEvidence(ch.HolonID, EvidenceRecord{
+			ID:      fmt.Sprintf("what-if-%s-%d", ch.HolonID, len(g.evidence[ch.HolonID])+1),
+			Type:    evidenceType,
+			Verdict: verdict,
+			Level:   level,
+		})
+		return nil
+
+	case ChangeSetCL:
+		if ch.CL < 0 || ch.CL > 3 {
+			return fmt.Errorf("%s: CL must be between 0 and 3, got %d", ch.Op, ch.CL)
+		}
+		for i, e := range g.deps[ch.HolonID] {
+			if e.ID == ch.DependencyID {
+				g.deps[ch.HolonID][i].CL = ch.CL
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %s does not depend on %s", ch.Op, ch.HolonID, ch.DependencyID)
+
+	case ChangeExpireEvidence:
+		for holonID, records := range g.evidence {
+			for i, e := range records {
+				if e.ID == ch.EvidenceID {
+					// Expiring now would barely move a gradual curve; show where it ends up
+					if e.ValidUntil == nil || !now.After(*e.ValidUntil) {
+						expired := now.Add(-time.Second)
+						g.evidence[holonID][i].ValidUntil = &expired
+					}
+					g.evidence[holonID][i].Exhausted = true
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%s: evidence %q not found", ch.Op, ch.EvidenceID)
+
+	default:
+		return fmt.Errorf("unknown change %q (use %s, %s or %s)", ch.Op, ChangeAddEvidence, ChangeSetCL, ChangeExpireEvidence)
+	}
+}
+
+// WhatIf evaluates holonID on the graph as-is and on a copy with the changes applied.
+// Nothing is persisted and g itself is left untouched.
+func (c *Calculator) WhatIf(ctx context.Context, g *Graph, holonID string, changes []Change) (*WhatIfResult, error) {
+	before, err := c.Evaluate(ctx, g, holonID)
+	if err != nil {
+		return nil, err
+	}
+
+	simulated := g.Clone()
+	for _, ch := range changes {
+		if err := simulated.Apply(ch, c.now()); err != nil {
+			return nil, err
+		}
+	}
+	after, err := c.Evaluate(ctx, simulated, holonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WhatIfResult{
+		HolonID: holonID,
+		Changes: changes,
+		Before:  before,
+		After:   after,
+		Delta:   after.FinalScore - before.FinalScore,
+	}, nil
+}
+
+// Sensitivity tries single changes across holonID's dependency closure — a new PASS on
+// each holon, raising each imperfect edge to CL3, expiring each evidence — and returns
+// the ones that move R_eff, largest |delta| first, at most limit results.
+func (c *Calculator) Sensitivity(ctx context.Context, g *Graph, holonID string, limit int) ([]*WhatIfResult, error) {
+	var candidates []Change
+	seen := map[string]bool{}
+	queue := []string{holonID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if _, ok := g.holons[id]; ok {
+			candidates = append(candidates, Change{Op: ChangeAddEvidence, HolonID: id})
+		}
+		for _, e := range g.evidence[id] {
+			if e.ValidUntil == nil || e.ValidUntil.After(c.now()) {
+				candidates = append(candidates, Change{Op: ChangeExpireEvidence, EvidenceID: e.ID})
+			}
+		}
+		for _, e := range g.deps[id] {
+			if e.CL < 3 {
+				candidates = append(candidates, Change{Op: ChangeSetCL, HolonID: id, DependencyID: e.ID, CL: 3})
+			}
+			queue = append(queue, e.ID)
+		}
+	}
+
+	var results []*WhatIfResult
+	for _, ch := range candidates {
+		result, err := c.WhatIf(ctx, g, holonID, []Change{ch})
+		if err != nil {
+			return nil, err
+		}
+		if math.Abs(result.Delta) > 1e-9 {
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return math.Abs(results[i].Delta) > math.Abs(results[j].Delta)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
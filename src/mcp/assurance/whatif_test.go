@@ -0,0 +1,146 @@
+package assurance
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWhatIf_RaiseCL(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedDiamond(t, db)
+
+	ctx := context.Background()
+	g, err := LoadGraph(ctx, db)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+
+	calc := New(db)
+	result, err := calc.WhatIf(ctx, g, "A", []Change{{Op: ChangeSetCL, HolonID: "A", DependencyID: "C", CL: 3}})
+	if err != nil {
+		t.Fatalf("WhatIf failed: %v", err)
+	}
+	if math.Abs(result.Before.FinalScore-0.4) > 1e-9 || math.Abs(result.After.FinalScore-0.5) > 1e-9 {
+		t.Errorf("Expected 0.4 → 0.5, got %.2f → %.2f", result.Before.FinalScore, result.After.FinalScore)
+	}
+	if math.Abs(result.Delta-0.1) > 1e-9 {
+		t.Errorf("Expected delta 0.1, got %f", result.Delta)
+	}
+	if result.After.WeakestLink != "B" {
+		t.Errorf("Expected new weakest link B, got %s", result.After.WeakestLink)
+	}
+
+	// The source graph and the cache are untouched
+	again, _ := calc.Evaluate(ctx, g, "A")
+	if math.Abs(again.FinalScore-0.4) > 1e-9 {
+		t.Errorf("WhatIf modified the graph: %.2f", again.FinalScore)
+	}
+	var cached float64
+	_ = db.QueryRow("SELECT cached_r_score FROM holons WHERE id = 'A'").Scan(&cached)
+	if cached != 0 {
+		t.Errorf("WhatIf wrote the cache: %.2f", cached)
+	}
+}
+
+func TestWhatIf_AddAndExpireEvidence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedDiamond(t, db)
+
+	ctx := context.Background()
+	g, _ := LoadGraph(ctx, db)
+	calc := New(db)
+
+	result, err := calc.WhatIf(ctx, g, "D", []Change{{Op: ChangeAddEvidence, HolonID: "D"}})
+	if err != nil {
+		t.Fatalf("WhatIf failed: %v", err)
+	}
+	if result.Delta <= 0 {
+		t.Errorf("Expected a PASS to raise D, got delta %f", result.Delta)
+	}
+
+	result, err = calc.WhatIf(ctx, g, "A", []Change{{Op: ChangeExpireEvidence, EvidenceID: "e-D"}})
+	if err != nil {
+		t.Fatalf("WhatIf failed: %v", err)
+	}
+	if result.Delta >= 0 {
+		t.Errorf("Expected expiring e-D to lower A, got delta %f", result.Delta)
+	}
+
+	for _, bad := range []Change{
+		{Op: "delete_holon"},
+		{Op: ChangeSetCL, HolonID: "A", DependencyID: "D", CL: 3},
+		{Op: ChangeExpireEvidence, EvidenceID: "missing"},
+		{Op: ChangeAddEvidence, HolonID: "D", Verdict: "maybe"},
+	} {
+		if _, err := calc.WhatIf(ctx, g, "A", []Change{bad}); err == nil {
+			t.Errorf("Expected error for %+v", bad)
+		}
+	}
+}
+
+func TestWhatIf_ExpireEvidenceDecaysToFloor(t *testing.T) {
+	for _, curve := range []DecayCurve{
+		{Kind: DecayLinear, WindowDays: 90, Floor: 0.2},
+		{Kind: DecayExponential, HalfLifeDays: 30, Floor: 0.2},
+	} {
+		t.Run(string(curve.Kind), func(t *testing.T) {
+			db := setupTestDB(t)
+			defer db.Close()
+
+			now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+			_, _ = db.Exec("INSERT INTO holons (id) VALUES ('A')")
+			_, _ = db.Exec("INSERT INTO evidence (id, holon_id, verdict, valid_until) VALUES ('e1', 'A', 'pass', ?)", now.Add(30*day))
+
+			policy := DefaultPolicy()
+			policy.Decay[DefaultDecayKey] = curve
+			calc := NewWithPolicy(db, policy)
+			calc.Now = func() time.Time { return now }
+			g, err := LoadGraph(context.Background(), db)
+			if err != nil {
+				t.Fatalf("LoadGraph failed: %v", err)
+			}
+
+			result, err := calc.WhatIf(context.Background(), g, "A", []Change{{Op: ChangeExpireEvidence, EvidenceID: "e1"}})
+			if err != nil {
+				t.Fatalf("WhatIf failed: %v", err)
+			}
+			if math.Abs(result.After.FinalScore-0.2) > 1e-9 {
+				t.Errorf("Expected the expired evidence at its floor 0.2, got %f", result.After.FinalScore)
+			}
+			if math.Abs(result.Delta+0.8) > 1e-9 {
+				t.Errorf("Expected delta -0.8, got %f", result.Delta)
+			}
+		})
+	}
+}
+
+func TestSensitivity_RanksByImpact(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedDiamond(t, db)
+
+	ctx := context.Background()
+	g, _ := LoadGraph(ctx, db)
+
+	results, err := New(db).Sensitivity(ctx, g, "A", 0)
+	if err != nil {
+		t.Fatalf("Sensitivity failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("Expected at least one change to move R_eff")
+	}
+	for i := 1; i < len(results); i++ {
+		if math.Abs(results[i].Delta) > math.Abs(results[i-1].Delta) {
+			t.Errorf("Results not sorted by impact: %f before %f", results[i-1].Delta, results[i].Delta)
+		}
+	}
+	for _, r := range results {
+		if r.Delta == 0 {
+			t.Errorf("Change %s has no effect but was reported", r.Changes[0])
+		}
+	}
+}
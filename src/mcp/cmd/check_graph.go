@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+var checkGraphCmd = &cobra.Command{
+	Use:   "check-graph",
+	Short: "Check knowledge graph integrity",
+	Long: `Check the knowledge graph in .quint/quint.db for:
+  - dependency cycles (strongly connected components)
+  - relations whose source or target does not exist
+  - evidence attached to holons that do not exist
+
+Exits with a non-zero status when problems are found, so it can run in CI.`,
+	Args: cobra.NoArgs,
+	RunE: runCheckGraph,
+}
+
+// runCheckGraph prints one integrity check and fails when that same check found problems
+func runCheckGraph(cmd *cobra.Command, args []string) error {
+	result, err := callTool(cmd, "quint_check_graph", nil)
+	if err != nil {
+		return err
+	}
+	if check, ok := result.(*fpf.GraphCheckResult); ok && !check.OK {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("graph integrity check failed")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,15 @@ import (
 //go:embed commands/*.md
 var embeddedCommands embed.FS
 
+// commandTemplates returns the embedded command templates rooted at the commands directory
+func commandTemplates() fs.FS {
+	sub, err := fs.Sub(embeddedCommands, "commands")
+	if err != nil {
+		panic(err) // The directory is embedded at build time
+	}
+	return sub
+}
+
 func installCommands(projectRoot string, platform string, local bool) (string, int, error) {
 	entries, err := embeddedCommands.ReadDir("commands")
 	if err != nil {
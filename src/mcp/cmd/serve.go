@@ -1,12 +1,11 @@
 package cmd
 
 import (
-	"database/sql"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
-	"path/filepath"
 
-	"github.com/m0n0x41d/quint-code/db"
 	"github.com/m0n0x41d/quint-code/internal/fpf"
 
 	"github.com/spf13/cobra"
@@ -18,15 +17,46 @@ var serveCmd = &cobra.Command{
 	Long: `Start the Model Context Protocol (MCP) server for AI tool integration.
 
 The server communicates via stdio and provides FPF tools to AI assistants
-like Claude Code, Cursor, Gemini CLI, and Codex CLI.
+like Claude Code, Cursor, Gemini CLI, and Codex CLI. The /q0-/q5 command
+templates are also served as MCP prompts, so any MCP client gets the
+workflow without installing command files.
+
+With --http the server instead speaks the MCP streamable HTTP transport at
+/mcp (POST for requests, GET for an SSE notification stream), so several
+agents or a dashboard can share one knowledge base. Requests must carry
+"Authorization: Bearer <token>"; the token comes from --token, then
+QUINT_HTTP_TOKEN, and is generated and printed to stderr otherwise.
 
 The project root is determined by:
   1. QUINT_PROJECT_ROOT environment variable (if set)
-  2. Current working directory (default)`,
+  2. Current working directory (default)
+
+One server can serve several projects (e.g. in a monorepo). Each --project
+root, and each root the client announces via MCP roots/list, can be selected
+with the "project" argument of any tool (a root path or its directory name).
+Every project keeps its own database, FSM state and audit log. Without the
+argument, calls go to the client's root if it announced exactly one, and to
+the server's project otherwise.
+
+With --read-only the server only offers tools that do not change the
+knowledge base (status, audit tree, R, what-if, scope, graph and decay
+reports) and refuses any call that would, e.g. quint_check_decay with
+deprecate or waive_id.`,
 	RunE: runServe,
 }
 
+var (
+	serveHTTP     string
+	serveToken    string
+	serveProjects []string
+	serveReadOnly bool
+)
+
 func init() {
+	serveCmd.Flags().StringVar(&serveHTTP, "http", "", "Serve streamable HTTP on this address (e.g. 127.0.0.1:8787) instead of stdio")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token for --http (default: $QUINT_HTTP_TOKEN or a generated one)")
+	serveCmd.Flags().StringArrayVar(&serveProjects, "project", nil, "Additional project root tool calls may select with the project argument (repeatable)")
+	serveCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "Refuse tool calls that change the knowledge base (for reviewers)")
 	rootCmd.AddCommand(serveCmd)
 }
 
@@ -40,30 +70,46 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	quintDir := filepath.Join(cwd, ".quint")
-	dbPath := filepath.Join(quintDir, "quint.db")
-
-	var database *db.Store
-	if _, err := os.Stat(dbPath); err == nil {
-		database, err = db.NewStore(dbPath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to open database: %v\n", err)
-		}
+	tools, err := fpf.OpenProject(cwd)
+	if err != nil {
+		return err
 	}
-
-	var rawDB *sql.DB
-	if database != nil {
-		rawDB = database.GetRawDB()
+	server := fpf.NewServer(tools)
+	server.SetVersion(Version)
+	server.SetReadOnly(serveReadOnly)
+	server.SetPrompts(fpf.NewPromptLibrary(commandTemplates()))
+	for _, project := range serveProjects {
+		if err := server.AddProject(project); err != nil {
+			return fmt.Errorf("invalid --project %s: %w", project, err)
+		}
 	}
 
-	fsm, err := fpf.LoadState("default", rawDB)
-	if err != nil {
-		return fmt.Errorf("failed to load state: %w", err)
+	if serveHTTP != "" {
+		token, err := httpToken()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "quint-code: serving MCP on http://%s/mcp\n", serveHTTP)
+		return server.ListenAndServeHTTP(serveHTTP, token)
 	}
 
-	tools := fpf.NewTools(fsm, cwd, database)
-	server := fpf.NewServer(tools)
 	server.Start()
-
 	return nil
 }
+
+// httpToken returns the bearer token for the HTTP transport, generating one if none is configured
+func httpToken() (string, error) {
+	if serveToken != "" {
+		return serveToken, nil
+	}
+	if token := os.Getenv("QUINT_HTTP_TOKEN"); token != "" {
+		return token, nil
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	fmt.Fprintf(os.Stderr, "quint-code: bearer token: %s\n", token)
+	return token, nil
+}
@@ -58,7 +58,7 @@ When user types slash commands, these are exposed via MCP:
 | ` + "`/q4-audit`" + ` | ` + "`quint_audit`" + ` | Audit | Calculate R_eff, check bias |
 | ` + "`/q5-decide`" + ` | ` + "`quint_decide`" + ` | Decision | Create DRR (Design Rationale Record) |
 | ` + "`/q-status`" + ` | ` + "`quint_status`" + ` | — | Show current phase |
-| ` + "`/q-query`" + ` | Search ` + "`.quint/`" + ` | — | Query knowledge base |
+| ` + "`/q-query`" + ` | ` + "`quint_query`" + ` | — | Query knowledge base |
 | ` + "`/q-decay`" + ` | ` + "`quint_check_decay`" + ` | — | Check evidence freshness |
 | ` + "`/q-actualize`" + ` | ` + "`quint_actualize`" + ` | — | Reconcile KB with code changes |
 
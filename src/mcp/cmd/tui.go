@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+	"github.com/m0n0x41d/quint-code/internal/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse the knowledge graph interactively",
+	Long: `Browse holons by layer in the terminal. Open a holon to see its R_eff, weakest link
+and decay status, drill into its dependencies and dependents, and deprecate it or
+waive its expired evidence. The view refreshes every few seconds, so changes made
+by agents through the MCP server show up while it is open.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := projectRoot()
+		if err != nil {
+			return err
+		}
+		dbPath := filepath.Join(root, ".quint", "quint.db")
+		if _, err := os.Stat(dbPath); err != nil {
+			return fmt.Errorf("no Quint project found (missing %s). Run 'quint-code init' first", dbPath)
+		}
+		tools, err := fpf.OpenProject(root)
+		if err != nil {
+			return err
+		}
+		if tools.DB != nil {
+			defer tools.DB.Close()
+		}
+		cmd.SilenceUsage = true
+		return tui.Run(cmd.Context(), tools, os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
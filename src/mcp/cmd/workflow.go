@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"github.com/spf13/cobra"
+)
+
+// The workflow commands run the MCP tools directly, through the same validation,
+// preconditions and phase transitions as tools/call.
+
+var outputJSON bool
+
+var (
+	proposeContent   string
+	proposeScope     string
+	proposeKind      string
+	proposeRationale string
+	proposeContext   string
+	proposeDependsOn []string
+	proposeCL        int
+	proposeFormality int
+
+	verifyVerdict   string
+	verifyChecks    string
+	verifyFormality int
+
+	testVerdict string
+	testType    string
+	testResult  string
+
+	auditRisks string
+
+	decideWinner          string
+	decideRejected        []string
+	decideContext         string
+	decideDecision        string
+	decideRationale       string
+	decideConsequences    string
+	decideCharacteristics string
+
+	decayDeprecate string
+	decayWaive     string
+	decayUntil     string
+	decayRationale string
+
+	queryLimit  int
+	searchLimit int
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the current FPF phase",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_status", nil)
+	},
+}
+
+var proposeCmd = &cobra.Command{
+	Use:   "propose <title>",
+	Short: "Propose a new hypothesis (L0)",
+	Long: `Propose a new hypothesis in L0.
+
+Use --depends-on for holons this hypothesis requires (they cap its R_eff via WLNK)
+and --context to group it with competing alternatives of the same decision.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_propose", map[string]interface{}{
+			"title":            args[0],
+			"content":          proposeContent,
+			"scope":            proposeScope,
+			"kind":             proposeKind,
+			"rationale":        proposeRationale,
+			"decision_context": proposeContext,
+			"depends_on":       proposeDependsOn,
+			"dependency_cl":    proposeCL,
+			"formality":        proposeFormality,
+		})
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <hypothesis-id>",
+	Short: "Record verification results (L0 -> L1)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arguments := map[string]interface{}{
+			"hypothesis_id": args[0],
+			"checks_json":   verifyChecks,
+			"verdict":       verifyVerdict,
+		}
+		if cmd.Flags().Changed("formality") {
+			arguments["formality"] = verifyFormality
+		}
+		return runTool(cmd, "quint_verify", arguments)
+	},
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test <hypothesis-id>",
+	Short: "Record validation results (L1 -> L2)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_test", map[string]interface{}{
+			"hypothesis_id": args[0],
+			"test_type":     testType,
+			"result":        testResult,
+			"verdict":       testVerdict,
+		})
+	},
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit <hypothesis-id>",
+	Short: "Record an audit of a hypothesis",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_audit", map[string]interface{}{
+			"hypothesis_id": args[0],
+			"risks":         auditRisks,
+		})
+	},
+}
+
+var decideCmd = &cobra.Command{
+	Use:   "decide <title>",
+	Short: "Finalize a decision (DRR)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_decide", map[string]interface{}{
+			"title":           args[0],
+			"winner_id":       decideWinner,
+			"rejected_ids":    decideRejected,
+			"context":         decideContext,
+			"decision":        decideDecision,
+			"rationale":       decideRationale,
+			"consequences":    decideConsequences,
+			"characteristics": decideCharacteristics,
+		})
+	},
+}
+
+var treeCmd = &cobra.Command{
+	Use:   "tree <holon-id>",
+	Short: "Show the assurance tree of a holon",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_audit_tree", map[string]interface{}{"holon_id": args[0]})
+	},
+}
+
+var reliabilityCmd = &cobra.Command{
+	Use:   "r <holon-id>",
+	Short: "Calculate R_eff and F of a holon",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_calculate_r", map[string]interface{}{"holon_id": args[0]})
+	},
+}
+
+var decayCmd = &cobra.Command{
+	Use:   "decay",
+	Short: "Report evidence freshness, deprecate holons or waive stale evidence",
+	Long: `Without flags, report stale evidence, holons about to decay below the assurance
+threshold and active waivers.
+
+  --deprecate <id>                     downgrade a hypothesis (L2 -> L1, L1 -> L0)
+  --waive <evidence-id> --until <date> --rationale <text>
+                                       accept stale evidence until the date`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_check_decay", map[string]interface{}{
+			"deprecate":       decayDeprecate,
+			"waive_id":        decayWaive,
+			"waive_until":     decayUntil,
+			"waive_rationale": decayRationale,
+		})
+	},
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query <query>...",
+	Short: "Find holons with the query language",
+	Long: `Find holons matching a query. All terms must hold; a leading '-' negates a term.
+
+  layer:L2 kind:system type:DRR id~cache   exact (:) or substring (~) match
+  title~redis content:"read through"       substring of the title or content
+  scope~payments                           substring of the declared scope
+  scope:"env=prod"                         the effective scope (G) admits the slice
+  depends:redis-cache                      direct dependency
+  context:caching-decision                 alternative of a decision context
+  r<0.6 f>=3                               R_eff and effective F
+  created>=2026-01-01 updated<2026-06-01   dates
+  caching "cache stampede"                 words and phrases in the title or content
+  sort:-r                                  sort by id, title, layer, kind, r, f, created or updated
+
+Quote the query for the shell, e.g. quint-code query 'layer:L2 r<0.6 sort:r'.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_query", map[string]interface{}{
+			"query": strings.Join(args, " "),
+			"limit": queryLimit,
+		})
+	},
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <words>...",
+	Short: "Full-text search over holons, DRRs and evidence",
+	Long: `Rank holons, DRRs and evidence by relevance to the words, all of which must match.
+"Quoted phrases" match exactly and cach* matches prefixes.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTool(cmd, "quint_search", map[string]interface{}{
+			"query": strings.Join(args, " "),
+			"limit": searchLimit,
+		})
+	},
+}
+
+func init() {
+	f := proposeCmd.Flags()
+	f.StringVar(&proposeContent, "content", "", "Description of the hypothesis (required)")
+	f.StringVar(&proposeScope, "scope", "global", "Scope (G), e.g. 'service=payments; env=prod'")
+	f.StringVar(&proposeKind, "kind", "system", "system (code/architecture) or episteme (process/methodology)")
+	f.StringVar(&proposeRationale, "rationale", "{}", "JSON: {anomaly, approach, alternatives_rejected}")
+	f.StringVar(&proposeContext, "context", "", "Decision context grouping competing alternatives")
+	f.StringSliceVar(&proposeDependsOn, "depends-on", nil, "IDs of holons this hypothesis requires")
+	f.IntVar(&proposeCL, "cl", 3, "Congruence level of the dependencies (1-3)")
+	f.IntVar(&proposeFormality, "formality", 0, "Formality level F0-F9")
+	_ = proposeCmd.MarkFlagRequired("content")
+
+	f = verifyCmd.Flags()
+	f.StringVar(&verifyVerdict, "verdict", "", "PASS, FAIL or REFINE (required)")
+	f.StringVar(&verifyChecks, "checks", "{}", "JSON of the checks performed")
+	f.IntVar(&verifyFormality, "formality", 0, "Formality level F0-F9 reached (applied on PASS)")
+	_ = verifyCmd.MarkFlagRequired("verdict")
+
+	f = testCmd.Flags()
+	f.StringVar(&testVerdict, "verdict", "", "PASS, FAIL or REFINE (required)")
+	f.StringVar(&testType, "type", "internal", "internal or research")
+	f.StringVar(&testResult, "result", "", "Test output or findings (required)")
+	_ = testCmd.MarkFlagRequired("verdict")
+	_ = testCmd.MarkFlagRequired("result")
+
+	auditCmd.Flags().StringVar(&auditRisks, "risks", "", "Risk analysis (required)")
+	_ = auditCmd.MarkFlagRequired("risks")
+
+	f = decideCmd.Flags()
+	f.StringVar(&decideWinner, "winner", "", "ID of the selected L2 hypothesis (required)")
+	f.StringSliceVar(&decideRejected, "reject", nil, "IDs of rejected L2 alternatives")
+	f.StringVar(&decideContext, "context", "", "Problem context (required)")
+	f.StringVar(&decideDecision, "decision", "", "The decision (required)")
+	f.StringVar(&decideRationale, "rationale", "", "Why this alternative won (required)")
+	f.StringVar(&decideConsequences, "consequences", "", "Consequences and trade-offs (required)")
+	f.StringVar(&decideCharacteristics, "characteristics", "", "Characteristics of the decision")
+	for _, name := range []string{"winner", "context", "decision", "rationale", "consequences"} {
+		_ = decideCmd.MarkFlagRequired(name)
+	}
+
+	f = decayCmd.Flags()
+	f.StringVar(&decayDeprecate, "deprecate", "", "Hypothesis ID to deprecate")
+	f.StringVar(&decayWaive, "waive", "", "Evidence ID to waive")
+	f.StringVar(&decayUntil, "until", "", "ISO date until which the waiver is valid")
+	f.StringVar(&decayRationale, "rationale", "", "Reason for accepting stale evidence")
+
+	queryCmd.Flags().IntVar(&queryLimit, "limit", 50, "Maximum number of holons listed")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of hits")
+
+	for _, c := range []*cobra.Command{statusCmd, proposeCmd, verifyCmd, testCmd, auditCmd, decideCmd, treeCmd, reliabilityCmd, decayCmd, queryCmd, searchCmd, checkGraphCmd} {
+		c.Flags().BoolVar(&outputJSON, "json", false, "Print the structured result as JSON")
+		rootCmd.AddCommand(c)
+	}
+}
+
+// projectRoot returns QUINT_PROJECT_ROOT or the working directory
+func projectRoot() (string, error) {
+	if root := os.Getenv("QUINT_PROJECT_ROOT"); root != "" {
+		return root, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return cwd, nil
+}
+
+// runTool calls an MCP tool on the project and prints its result as markdown or, with --json, as JSON
+func runTool(cmd *cobra.Command, name string, arguments map[string]interface{}) error {
+	_, err := callTool(cmd, name, arguments)
+	return err
+}
+
+// callTool is runTool for commands that also act on the result
+func callTool(cmd *cobra.Command, name string, arguments map[string]interface{}) (fpf.ToolResult, error) {
+	root, err := projectRoot()
+	if err != nil {
+		return nil, err
+	}
+	dbPath := filepath.Join(root, ".quint", "quint.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("no Quint project found (missing %s). Run 'quint-code init' first", dbPath)
+	}
+	tools, err := fpf.OpenProject(root)
+	if err != nil {
+		return nil, err
+	}
+	if tools.DB != nil {
+		defer tools.DB.Close()
+	}
+
+	args, err := toolArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tools.CallTool(cmd.Context(), name, args)
+	if err != nil {
+		cmd.SilenceUsage = !errors.As(err, new(*fpf.ErrInvalidArguments))
+		return nil, err
+	}
+
+	if outputJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return result, enc.Encode(result)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), result.Markdown())
+	return result, nil
+}
+
+// toolArguments converts flag values to JSON values, leaving out empty optional ones
+func toolArguments(arguments map[string]interface{}) (map[string]interface{}, error) {
+	for name, v := range arguments {
+		switch v := v.(type) {
+		case string:
+			if v == "" {
+				delete(arguments, name)
+			}
+		case []string:
+			if len(v) == 0 {
+				delete(arguments, name)
+			}
+		}
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
@@ -37,6 +37,16 @@ var migrations = []struct {
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 	},
+	{
+		version:     4,
+		description: "Add formality (F0-F9) to holons for the F-G-R assurance tuple",
+		sql:         `ALTER TABLE holons ADD COLUMN formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9)`,
+	},
+	{
+		version:     5,
+		description: "Add search_index FTS5 table over holon, DRR and evidence content",
+		sql:         searchIndexSchema + ";" + searchIndexBackfill,
+	},
 }
 
 // RunMigrations applies all pending migrations to the database.
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"path/filepath"
 	"testing"
@@ -54,6 +55,9 @@ func TestRunMigrations_ExistingDatabase(t *testing.T) {
 	if _, err := conn.Exec(oldSchema); err != nil {
 		t.Fatalf("Failed to create old schema: %v", err)
 	}
+	if _, err := conn.Exec(`INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('old', 'hypothesis', 'L1', 'Legacy cache', 'Existing content', 'default')`); err != nil {
+		t.Fatalf("Failed to insert holon: %v", err)
+	}
 	conn.Close()
 
 	// Now open with NewStore which runs migrations
@@ -66,12 +70,17 @@ func TestRunMigrations_ExistingDatabase(t *testing.T) {
 	// Verify new columns exist by querying them
 	var parentID sql.NullString
 	var cachedRScore sql.NullFloat64
-	err = store.conn.QueryRow("SELECT parent_id, cached_r_score FROM holons LIMIT 1").Scan(&parentID, &cachedRScore)
-	// Will get sql.ErrNoRows since table is empty, but query should not fail due to missing columns
-	if err != nil && err != sql.ErrNoRows {
+	var formality sql.NullInt64
+	err = store.conn.QueryRow("SELECT parent_id, cached_r_score, formality FROM holons LIMIT 1").Scan(&parentID, &cachedRScore, &formality)
+	if err != nil {
 		t.Errorf("New columns should exist: %v", err)
 	}
 
+	// Existing holons are backfilled into the search index
+	if hits, err := store.Search(context.Background(), "legacy", 10); err != nil || len(hits) != 1 || hits[0].HolonID != "old" {
+		t.Errorf("Expected the existing holon in the search index, got %+v (err %v)", hits, err)
+	}
+
 	// Verify migrations are recorded
 	var count int
 	store.conn.QueryRow("SELECT COUNT(*) FROM schema_version").Scan(&count)
@@ -55,6 +55,7 @@ type Holon struct {
 	Scope        sql.NullString
 	ParentID     sql.NullString
 	CachedRScore sql.NullFloat64
+	Formality    sql.NullInt64
 	CreatedAt    sql.NullTime
 	UpdatedAt    sql.NullTime
 }
@@ -622,7 +622,7 @@ func (q *Queries) GetEvidenceWithCarrier(ctx context.Context, db DBTX) ([]Eviden
 }
 
 const getHolon = `-- name: GetHolon :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE id = ? LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, created_at, updated_at FROM holons WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, error) {
@@ -639,6 +639,7 @@ func (q *Queries) GetHolon(ctx context.Context, db DBTX, id string) (Holon, erro
 		&i.Scope,
 		&i.ParentID,
 		&i.CachedRScore,
+		&i.Formality,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -722,7 +723,7 @@ func (q *Queries) GetHolonTitle(ctx context.Context, db DBTX, id string) (string
 }
 
 const getHolonsByParent = `-- name: GetHolonsByParent :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE parent_id = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, created_at, updated_at FROM holons WHERE parent_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.NullString) ([]Holon, error) {
@@ -745,6 +746,7 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 			&i.Scope,
 			&i.ParentID,
 			&i.CachedRScore,
+			&i.Formality,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -762,7 +764,7 @@ func (q *Queries) GetHolonsByParent(ctx context.Context, db DBTX, parentID sql.N
 }
 
 const getLatestHolonByContext = `-- name: GetLatestHolonByContext :one
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, created_at, updated_at FROM holons WHERE context_id = ? ORDER BY updated_at DESC LIMIT 1
 `
 
 func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextID string) (Holon, error) {
@@ -779,6 +781,7 @@ func (q *Queries) GetLatestHolonByContext(ctx context.Context, db DBTX, contextI
 		&i.Scope,
 		&i.ParentID,
 		&i.CachedRScore,
+		&i.Formality,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -956,8 +959,49 @@ func (q *Queries) ListAllHolonIDs(ctx context.Context, db DBTX) ([]string, error
 	return items, nil
 }
 
+const listHolons = `-- name: ListHolons :many
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, created_at, updated_at FROM holons ORDER BY id
+`
+
+func (q *Queries) ListHolons(ctx context.Context, db DBTX) ([]Holon, error) {
+	rows, err := db.QueryContext(ctx, listHolons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Holon
+	for rows.Next() {
+		var i Holon
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Kind,
+			&i.Layer,
+			&i.Title,
+			&i.Content,
+			&i.ContextID,
+			&i.Scope,
+			&i.ParentID,
+			&i.CachedRScore,
+			&i.Formality,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listHolonsByLayer = `-- name: ListHolonsByLayer :many
-SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, created_at, updated_at FROM holons WHERE layer = ? ORDER BY created_at DESC
+SELECT id, type, kind, layer, title, content, context_id, scope, parent_id, cached_r_score, formality, created_at, updated_at FROM holons WHERE layer = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string) ([]Holon, error) {
@@ -980,6 +1024,7 @@ func (q *Queries) ListHolonsByLayer(ctx context.Context, db DBTX, layer string)
 			&i.Scope,
 			&i.ParentID,
 			&i.CachedRScore,
+			&i.Formality,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -1026,6 +1071,21 @@ func (q *Queries) RecordWork(ctx context.Context, db DBTX, arg RecordWorkParams)
 	return err
 }
 
+const updateHolonFormality = `-- name: UpdateHolonFormality :exec
+UPDATE holons SET formality = ?, updated_at = ? WHERE id = ?
+`
+
+type UpdateHolonFormalityParams struct {
+	Formality sql.NullInt64
+	UpdatedAt sql.NullTime
+	ID        string
+}
+
+func (q *Queries) UpdateHolonFormality(ctx context.Context, db DBTX, arg UpdateHolonFormalityParams) error {
+	_, err := db.ExecContext(ctx, updateHolonFormality, arg.Formality, arg.UpdatedAt, arg.ID)
+	return err
+}
+
 const updateHolonLayer = `-- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?
 `
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// Full-text search over holon, DRR and evidence content. The search_index FTS5 table is
+// written next to holons and evidence by CreateHolon and AddEvidence; sqlc does not
+// understand FTS5, so these queries are written by hand.
+
+const searchIndexSchema = `CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+	holon_id UNINDEXED,
+	source UNINDEXED,
+	source_id UNINDEXED,
+	title,
+	content,
+	tokenize = 'porter unicode61'
+)`
+
+const searchIndexBackfill = `
+INSERT INTO search_index (holon_id, source, source_id, title, content)
+SELECT id, CASE WHEN type = 'DRR' THEN 'drr' ELSE 'holon' END, id, title, content FROM holons;
+INSERT INTO search_index (holon_id, source, source_id, title, content)
+SELECT holon_id, 'evidence', id, type, content FROM evidence`
+
+const indexDocument = `INSERT INTO search_index (holon_id, source, source_id, title, content) VALUES (?, ?, ?, ?, ?)`
+
+// The title column weighs five times the content in the BM25 rank
+const searchHits = `SELECT s.holon_id, s.source, s.source_id, COALESCE(h.title, s.title), COALESCE(h.layer, ''),
+	snippet(search_index, -1, '**', '**', '…', 16), bm25(search_index, 0.0, 0.0, 0.0, 5.0, 1.0) AS rank
+FROM search_index s
+LEFT JOIN holons h ON h.id = s.holon_id
+WHERE search_index MATCH ?
+ORDER BY rank
+LIMIT ?`
+
+// SearchHit is one ranked full-text match
+type SearchHit struct {
+	HolonID  string
+	Source   string // holon, drr or evidence
+	SourceID string // Holon or evidence ID
+	Title    string // Title of the holon
+	Layer    string
+	Snippet  string // Matching fragment, matched terms in **bold**
+	Rank     float64
+}
+
+// Search returns the best matches for a search text: words (all must match), "quoted phrases"
+// and prefixes like cach*. Lower rank is better.
+func (s *Store) Search(ctx context.Context, text string, limit int) ([]SearchHit, error) {
+	match := ftsQuery(text)
+	if match == "" {
+		return nil, nil
+	}
+	rows, err := s.conn.QueryContext(ctx, searchHits, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hits []SearchHit
+	for rows.Next() {
+		var h SearchHit
+		if err := rows.Scan(&h.HolonID, &h.Source, &h.SourceID, &h.Title, &h.Layer, &h.Snippet, &h.Rank); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// ftsQuery turns search text into an FTS5 expression. Every word and phrase is quoted, so
+// punctuation like '-' or ':' is never read as FTS5 syntax; a trailing * keeps prefix search.
+func ftsQuery(text string) string {
+	var terms []string
+	for _, field := range splitSearchText(text) {
+		prefix := strings.HasSuffix(field, "*")
+		field = strings.Trim(field, `"*`)
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+// splitSearchText splits on whitespace, keeping double-quoted phrases together
+func splitSearchText(text string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuote := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// withTx runs fn in a transaction, committing when it returns nil
+func (s *Store) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
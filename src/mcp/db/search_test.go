@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStore_Search(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	_ = store.CreateHolon(ctx, "redis-cache", "hypothesis", "system", "L2", "Redis caching", "Read-through cache for sessions", "default", "", "")
+	_ = store.CreateHolon(ctx, "cdn-edge", "hypothesis", "system", "L1", "CDN edge", "Serve static assets from the edge", "default", "", "")
+	_ = store.CreateHolon(ctx, "caching-drr", "DRR", "", "DRR", "Caching decision", "Chose Redis over the CDN for session data", "default", "", "redis-cache")
+	if err := store.AddEvidence(ctx, "e1", "cdn-edge", "test", "Benchmark: p99 latency dropped under cache stampede", "pass", "L2", "test-runner", ""); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+
+	hits, err := store.Search(ctx, "caching", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(hits) < 2 || (hits[0].HolonID != "redis-cache" && hits[0].HolonID != "caching-drr") {
+		t.Fatalf("Expected title matches ranked first, got %+v", hits)
+	}
+	sources := map[string]string{}
+	for _, h := range hits {
+		sources[h.SourceID] = h.Source
+	}
+	if sources["caching-drr"] != "drr" || sources["e1"] != "evidence" {
+		t.Errorf("Expected the DRR and the evidence among the hits (porter stemming), got %+v", hits)
+	}
+
+	hits, err = store.Search(ctx, `"cache stampede" p99`, 10)
+	if err != nil {
+		t.Fatalf("Phrase search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].HolonID != "cdn-edge" || hits[0].Title != "CDN edge" || hits[0].Layer != "L1" {
+		t.Fatalf("Expected the evidence hit linked to cdn-edge, got %+v", hits)
+	}
+	if !strings.Contains(hits[0].Snippet, "**cache stampede**") {
+		t.Errorf("Expected highlighted snippet, got %q", hits[0].Snippet)
+	}
+
+	// FTS5 syntax characters are searched as text instead of failing the query
+	for _, text := range []string{"read-through", "sess*", "title:redis", `"unbalanced`} {
+		if _, err := store.Search(ctx, text, 10); err != nil {
+			t.Errorf("Search(%q) failed: %v", text, err)
+		}
+	}
+	if hits, _ := store.Search(ctx, "sess*", 10); len(hits) != 2 {
+		t.Errorf("Expected prefix search to match both session holons, got %+v", hits)
+	}
+}
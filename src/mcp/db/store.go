@@ -21,6 +21,7 @@ CREATE TABLE IF NOT EXISTS holons (
 	scope TEXT,
 	parent_id TEXT REFERENCES holons(id),
 	cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
+	formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
 	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -123,18 +124,28 @@ func (s *Store) Close() error {
 
 func (s *Store) CreateHolon(ctx context.Context, id, typ, kind, layer, title, content, contextID, scope, parentID string) error {
 	now := sql.NullTime{Time: time.Now(), Valid: true}
-	return s.q.CreateHolon(ctx, s.conn, CreateHolonParams{
-		ID:        id,
-		Type:      typ,
-		Kind:      toNullString(kind),
-		Layer:     layer,
-		Title:     title,
-		Content:   content,
-		ContextID: contextID,
-		Scope:     toNullString(scope),
-		ParentID:  toNullString(parentID),
-		CreatedAt: now,
-		UpdatedAt: now,
+	source := "holon"
+	if typ == "DRR" {
+		source = "drr"
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := s.q.CreateHolon(ctx, tx, CreateHolonParams{
+			ID:        id,
+			Type:      typ,
+			Kind:      toNullString(kind),
+			Layer:     layer,
+			Title:     title,
+			Content:   content,
+			ContextID: contextID,
+			Scope:     toNullString(scope),
+			ParentID:  toNullString(parentID),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, indexDocument, id, source, id, title, content)
+		return err
 	})
 }
 
@@ -150,6 +161,10 @@ func (s *Store) ListAllHolonIDs(ctx context.Context) ([]string, error) {
 	return s.q.ListAllHolonIDs(ctx, s.conn)
 }
 
+func (s *Store) ListHolons(ctx context.Context) ([]Holon, error) {
+	return s.q.ListHolons(ctx, s.conn)
+}
+
 func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
 	return s.q.UpdateHolonLayer(ctx, s.conn, UpdateHolonLayerParams{
 		ID:        id,
@@ -158,6 +173,14 @@ func (s *Store) UpdateHolonLayer(ctx context.Context, id, layer string) error {
 	})
 }
 
+func (s *Store) UpdateHolonFormality(ctx context.Context, id string, formality int) error {
+	return s.q.UpdateHolonFormality(ctx, s.conn, UpdateHolonFormalityParams{
+		ID:        id,
+		Formality: sql.NullInt64{Int64: int64(formality), Valid: true},
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
 func (s *Store) RecordWork(ctx context.Context, id, methodRef, performerRef string, startedAt, endedAt time.Time, ledger string) error {
 	return s.q.RecordWork(ctx, s.conn, RecordWorkParams{
 		ID:             id,
@@ -182,16 +205,22 @@ func (s *Store) AddEvidence(ctx context.Context, id, holonID, typ, content, verd
 		}
 	}
 
-	return s.q.AddEvidence(ctx, s.conn, AddEvidenceParams{
-		ID:             id,
-		HolonID:        holonID,
-		Type:           typ,
-		Content:        content,
-		Verdict:        verdict,
-		AssuranceLevel: toNullString(assuranceLevel),
-		CarrierRef:     toNullString(carrierRef),
-		ValidUntil:     vUntil,
-		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if err := s.q.AddEvidence(ctx, tx, AddEvidenceParams{
+			ID:             id,
+			HolonID:        holonID,
+			Type:           typ,
+			Content:        content,
+			Verdict:        verdict,
+			AssuranceLevel: toNullString(assuranceLevel),
+			CarrierRef:     toNullString(carrierRef),
+			ValidUntil:     vUntil,
+			CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+		}); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, indexDocument, holonID, "evidence", id, typ, content)
+		return err
 	})
 }
 
@@ -241,6 +270,10 @@ func (s *Store) GetHolonLineage(ctx context.Context, id string) ([]GetHolonLinea
 	return s.q.GetHolonLineage(ctx, s.conn, id)
 }
 
+func (s *Store) ListHolonsByLayer(ctx context.Context, layer string) ([]Holon, error) {
+	return s.q.ListHolonsByLayer(ctx, s.conn, layer)
+}
+
 func (s *Store) CountHolonsByLayer(ctx context.Context, contextID string) ([]CountHolonsByLayerRow, error) {
 	return s.q.CountHolonsByLayer(ctx, s.conn, contextID)
 }
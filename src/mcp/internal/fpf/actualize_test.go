@@ -62,7 +62,7 @@ func TestActualize_GitReconciliation(t *testing.T) {
 	tools := fpf.NewTools(fsm, tempDir, database)
 
 	// 3. First Actualize call: Should initialize baseline
-	report1, err := tools.Actualize()
+	report1, err := tools.Actualize(t.Context())
 	if err != nil {
 		t.Fatalf("First Actualize failed: %v", err)
 	}
@@ -82,7 +82,7 @@ func TestActualize_GitReconciliation(t *testing.T) {
 	}
 
 	// 5. Second Actualize call: Should detect changes
-	report2, err := tools.Actualize()
+	report2, err := tools.Actualize(t.Context())
 	if err != nil {
 		t.Fatalf("Second Actualize failed: %v", err)
 	}
@@ -94,7 +94,7 @@ func TestActualize_GitReconciliation(t *testing.T) {
 	}
 
 	// 6. Third Actualize call: Should be clean
-	report3, err := tools.Actualize()
+	report3, err := tools.Actualize(t.Context())
 	if err != nil {
 		t.Fatalf("Third Actualize failed: %v", err)
 	}
@@ -125,7 +125,7 @@ func TestActualize_LegacyMigration(t *testing.T) {
 	tools := fpf.NewTools(fsm, tempDir, nil)
 
 	// Run Actualize
-	report, err := tools.Actualize()
+	report, err := tools.Actualize(t.Context())
 	if err != nil {
 		t.Fatalf("Actualize failed during migration: %v", err)
 	}
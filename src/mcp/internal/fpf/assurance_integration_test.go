@@ -147,6 +147,54 @@ func TestAssuranceGuard_RespectsConfigurableThreshold(t *testing.T) {
 	}
 }
 
+func TestAssuranceGuard_LowerBoundGate(t *testing.T) {
+	fsm, database, tempDir := setupAssuranceTestEnv(t)
+	rawDB := database.GetRawDB()
+
+	l2Dir := filepath.Join(tempDir, ".quint", "knowledge", "L2")
+	os.MkdirAll(l2Dir, 0755)
+	l2File := filepath.Join(l2Dir, "thin-holon.md")
+	os.WriteFile(l2File, []byte("Thinly evidenced hypothesis"), 0644)
+
+	// A single PASS: point estimate 1.0, but the 90% lower bound is only ~0.22
+	_, err := rawDB.Exec("INSERT INTO holons (id, type, layer, title, content, context_id) VALUES ('thin-holon', 'hypothesis', 'L2', 'Thin', 'Content', 'ctx')")
+	if err != nil {
+		t.Fatalf("Failed to insert holon: %v", err)
+	}
+	_, err = rawDB.Exec("INSERT INTO evidence (id, holon_id, type, content, verdict, valid_until) VALUES ('e1', 'thin-holon', 'test', 'Passed once', 'pass', ?)", time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to insert evidence: %v", err)
+	}
+
+	ra := fpf.RoleAssignment{Role: fpf.RoleDecider, SessionID: "test", Context: "test"}
+	ev := &fpf.EvidenceStub{URI: l2File, Type: "hypothesis", HolonID: "thin-holon"}
+
+	if ok, msg := fsm.CanTransition(fpf.PhaseOperation, ra, ev); !ok {
+		t.Errorf("Expected point-estimate gate to ALLOW, got: %s", msg)
+	}
+
+	fsm.PolicyDir = filepath.Join(tempDir, ".quint")
+	if err := os.WriteFile(filepath.Join(fsm.PolicyDir, assurance.PolicyFile), []byte(`{"gate":"lower"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, msg := fsm.CanTransition(fpf.PhaseOperation, ra, ev)
+	if ok {
+		t.Errorf("Expected lower-bound gate to BLOCK a single PASS")
+	}
+	if !strings.Contains(msg, "lower bound") {
+		t.Errorf("Expected denial to mention the lower bound, got: %s", msg)
+	}
+
+	// An invalid policy blocks the gate like it fails the tools, instead of falling back
+	if err := os.WriteFile(filepath.Join(fsm.PolicyDir, assurance.PolicyFile), []byte(`{"gate":"median"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, msg := fsm.CanTransition(fpf.PhaseOperation, ra, ev); ok || !strings.Contains(msg, "Failed to load assurance policy") {
+		t.Errorf("Expected an invalid policy to deny the transition, got %v: %s", ok, msg)
+	}
+}
+
 func TestEvidenceDecay_PenalizesExpired(t *testing.T) {
 	fsm, database, _ := setupAssuranceTestEnv(t)
 	rawDB := database.GetRawDB()
@@ -208,7 +256,7 @@ func TestAuditVisualization_ReturnsTree(t *testing.T) {
 	fsm, _ := fpf.LoadState("default", rawDB)
 	tools := fpf.NewTools(fsm, tempDir, database)
 
-	tree, err := tools.VisualizeAudit("parent")
+	tree, err := tools.VisualizeAudit(t.Context(), "parent")
 	if err != nil {
 		t.Fatalf("VisualizeAudit failed: %v", err)
 	}
@@ -68,8 +68,22 @@ type TransitionRule struct {
 
 // FSM manages the state transitions
 type FSM struct {
-	State State
-	DB    *sql.DB
+	State     State
+	DB        *sql.DB
+	PolicyDir string // Directory of assurance.json; empty means the built-in policy
+
+	// OnPhaseChange is called by SaveState when the saved phase differs from the previous one
+	OnPhaseChange func(from, to Phase)
+	savedPhase    Phase // Phase at the last SaveState; empty means idle
+}
+
+// AssurancePolicy reads the project policy (assurance.json in PolicyDir) at each use, so the
+// Operation gate and the tools always score with the same policy, edits included
+func (f *FSM) AssurancePolicy() (*assurance.Policy, error) {
+	if f.PolicyDir == "" {
+		return assurance.DefaultPolicy(), nil
+	}
+	return assurance.LoadPolicy(f.PolicyDir)
 }
 
 // LoadState reads state from fpf_state table in SQLite
@@ -215,6 +229,17 @@ func (f *FSM) SaveState(contextID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to save state: %w", err)
 	}
+
+	from := f.savedPhase
+	if from == "" {
+		from = PhaseIdle
+	}
+	if from != f.State.Phase {
+		f.savedPhase = f.State.Phase
+		if f.OnPhaseChange != nil {
+			f.OnPhaseChange(from, f.State.Phase)
+		}
+	}
 	return nil
 }
 
@@ -276,14 +301,23 @@ func (f *FSM) CanTransition(target Phase, assignment RoleAssignment, evidence *E
 			return false, "Transition to Operation requires a specific Holon ID in evidence stub"
 		}
 
-		calc := assurance.New(f.DB)
+		policy, err := f.AssurancePolicy()
+		if err != nil {
+			return false, fmt.Sprintf("Failed to load assurance policy: %v", err)
+		}
+		calc := assurance.NewWithPolicy(f.DB, policy)
 		report, err := calc.CalculateReliability(context.Background(), evidence.HolonID)
 		if err != nil {
 			return false, fmt.Sprintf("Failed to calculate assurance: %v", err)
 		}
 
 		threshold := f.GetAssuranceThreshold()
-		if report.FinalScore < threshold {
+		if calc.Policy.Gate == assurance.GateLower {
+			if report.Interval.Lower < threshold {
+				return false, fmt.Sprintf("Transition Denied: Reliability lower bound (%.2f, %.0f%% credible; point %.2f) is below threshold (%.2f). Weakest link: %s",
+					report.Interval.Lower, calc.Policy.Credibility*100, report.FinalScore, threshold, report.WeakestLink)
+			}
+		} else if report.FinalScore < threshold {
 			return false, fmt.Sprintf("Transition Denied: Reliability (%.2f) is below threshold (%.2f). Weakest link: %s", report.FinalScore, threshold, report.WeakestLink)
 		}
 	}
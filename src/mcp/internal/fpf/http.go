@@ -0,0 +1,294 @@
+package fpf
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Streamable HTTP transport constants
+const (
+	sessionHeader     = "Mcp-Session-Id"
+	protocolHeader    = "MCP-Protocol-Version"
+	maxHTTPBodyBytes  = 10 << 20
+	sseKeepAlive      = 30 * time.Second
+	sseBufferedEvents = 64
+	sessionIdleLimit  = 30 * time.Minute
+)
+
+// httpTransport serves the MCP streamable HTTP transport on a single endpoint:
+// POST carries JSON-RPC messages (one or a batch), GET opens an SSE stream for server
+// notifications, DELETE ends the session. Every request needs the bearer token.
+// Sessions a client abandons without DELETE expire after idleLimit without requests or streams.
+type httpTransport struct {
+	server    *Server
+	token     string
+	idleLimit time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+// httpSession is a client session plus what is needed to tell when it was abandoned
+type httpSession struct {
+	*session
+	active   int       // Requests and streams in progress
+	lastUsed time.Time // When the last of them ended
+}
+
+// HTTPHandler returns the streamable HTTP handler for the server, guarded by a bearer token
+func (s *Server) HTTPHandler(token string) http.Handler {
+	return &httpTransport{server: s, token: token, idleLimit: sessionIdleLimit, sessions: make(map[string]*httpSession)}
+}
+
+// ListenAndServeHTTP serves the MCP endpoint at /mcp on addr until the listener fails
+func (s *Server) ListenAndServeHTTP(addr, token string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", s.HTTPHandler(token))
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+func (h *httpTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="quint-code"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !allowedOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleStream(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *httpTransport) authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+// allowedOrigin guards against DNS rebinding: browsers may only call from the same host or localhost
+func allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if reqHost, _, err := net.SplitHostPort(r.Host); err == nil && host == reqHost {
+		return true
+	}
+	return host == r.Host || host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// checkSession validates the Mcp-Session-Id and MCP-Protocol-Version headers and writes the
+// error response if they are not valid. A valid session counts as in use until the caller
+// releases it.
+func (h *httpTransport) checkSession(w http.ResponseWriter, r *http.Request) (string, *httpSession, bool) {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "missing "+sessionHeader+" header", http.StatusBadRequest)
+		return "", nil, false
+	}
+	if version := r.Header.Get(protocolHeader); version != "" && !isSupportedProtocol(version) {
+		http.Error(w, "unsupported "+protocolHeader+": "+version, http.StatusBadRequest)
+		return "", nil, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sess, ok := h.sessions[id]
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return "", nil, false
+	}
+	sess.active++
+	return id, sess, true
+}
+
+// release marks the end of a request or stream that checkSession let through
+func (h *httpTransport) release(sess *httpSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sess.active--
+	sess.lastUsed = time.Now()
+}
+
+// expireSessions ends the sessions that have been idle for longer than idleLimit
+func (h *httpTransport) expireSessions() {
+	var expired []*session
+	h.mu.Lock()
+	for id, sess := range h.sessions {
+		if sess.active == 0 && time.Since(sess.lastUsed) > h.idleLimit {
+			delete(h.sessions, id)
+			expired = append(expired, sess.session)
+		}
+	}
+	h.mu.Unlock()
+	for _, sess := range expired {
+		h.server.endSession(sess)
+	}
+}
+
+func (h *httpTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPBodyBytes))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+	var messages []JSONRPCRequest
+	if batch {
+		err = json.Unmarshal(trimmed, &messages)
+	} else {
+		var req JSONRPCRequest
+		err = json.Unmarshal(trimmed, &req)
+		messages = []JSONRPCRequest{req}
+	}
+	if err != nil || len(messages) == 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse(nil, -32700, "Parse error"))
+		return
+	}
+
+	initialize := len(messages) == 1 && messages[0].Method == "initialize"
+	sess := &session{}
+	if initialize {
+		// Sessions are only created here, so sweeping here keeps the map bounded
+		h.expireSessions()
+	} else {
+		_, entry, ok := h.checkSession(w, r)
+		if !ok {
+			return
+		}
+		defer h.release(entry)
+		sess = entry.session
+	}
+
+	ctx := withSession(r.Context(), sess)
+	var responses []*JSONRPCResponse
+	for _, req := range messages {
+		if resp := h.server.Handle(ctx, req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+
+	if initialize && len(responses) == 1 && responses[0].Error == nil {
+		id := uuid.New().String()
+		h.mu.Lock()
+		h.sessions[id] = &httpSession{session: sess, lastUsed: time.Now()}
+		h.mu.Unlock()
+		h.server.startSession(sess)
+		w.Header().Set(sessionHeader, id)
+	}
+
+	switch {
+	case len(responses) == 0:
+		// Only notifications or responses: nothing to answer
+		w.WriteHeader(http.StatusAccepted)
+	case batch:
+		writeJSON(w, http.StatusOK, responses)
+	default:
+		writeJSON(w, http.StatusOK, responses[0])
+	}
+}
+
+// handleStream keeps an SSE stream open and forwards server notifications to it
+func (h *httpTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "GET requires Accept: text/event-stream", http.StatusMethodNotAllowed)
+		return
+	}
+	_, entry, ok := h.checkSession(w, r)
+	if !ok {
+		return
+	}
+	defer h.release(entry)
+	sess := entry.session
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events := make(chan []byte, sseBufferedEvents)
+	send := func(data []byte) {
+		select {
+		case events <- data:
+		default:
+			// A stalled client must not block other transports; it misses this message
+		}
+	}
+	unsubscribe := h.server.subscribe(send)
+	defer unsubscribe()
+	// Server requests such as roots/list reach the client over its stream
+	sess.setSender(send)
+	defer sess.setSender(nil)
+	h.server.refreshRoots(withSession(r.Context(), sess))
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *httpTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id, sess, ok := h.checkSession(w, r)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	delete(h.sessions, id)
+	h.mu.Unlock()
+	h.server.endSession(sess.session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,306 @@
+package fpf
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupHTTP(t *testing.T) (*Server, *httptest.Server) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	ts := httptest.NewServer(server.HTTPHandler("secret"))
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+func postMCP(t *testing.T, ts *httptest.Server, session, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if session != "" {
+		req.Header.Set(sessionHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func initializeHTTP(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	resp := postMCP(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize: status %d", resp.StatusCode)
+	}
+	session := resp.Header.Get(sessionHeader)
+	if session == "" {
+		t.Fatal("initialize did not return a session ID")
+	}
+	return session
+}
+
+func TestHTTP_RequiresToken(t *testing.T) {
+	_, ts := setupHTTP(t)
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong token, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTP_SessionAndRequests(t *testing.T) {
+	_, ts := setupHTTP(t)
+
+	if resp := postMCP(t, ts, "", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a session, got %d", resp.StatusCode)
+	}
+	if resp := postMCP(t, ts, "bogus", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown session, got %d", resp.StatusCode)
+	}
+
+	session := initializeHTTP(t, ts)
+
+	if resp := postMCP(t, ts, session, `{"jsonrpc":"2.0","method":"notifications/initialized"}`); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected 202 for a notification, got %d", resp.StatusCode)
+	}
+
+	resp := postMCP(t, ts, session, `[{"jsonrpc":"2.0","id":3,"method":"tools/list"},{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}]`)
+	var batch []JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("Failed to decode batch response: %v", err)
+	}
+	if len(batch) != 2 || batch[0].Error != nil || batch[1].Error != nil {
+		t.Errorf("Expected two successful responses, got %+v", batch)
+	}
+}
+
+func TestHTTP_StreamReceivesNotifications(t *testing.T) {
+	server, ts := setupHTTP(t)
+	session := initializeHTTP(t, ts)
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, session)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for the SSE stream, got %d", resp.StatusCode)
+	}
+
+	// The stream is subscribed once the headers are flushed
+	server.Notify("quint/test", map[string]string{"hello": "world"})
+
+	lines := make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "data: ") {
+				if !strings.Contains(line, `"method":"quint/test"`) {
+					t.Errorf("Unexpected event: %s", line)
+				}
+				return
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("No notification received on the SSE stream")
+		}
+	}
+}
+
+func TestHTTP_SessionKeepsNegotiatedProtocol(t *testing.T) {
+	_, ts := setupHTTP(t)
+
+	resp := postMCP(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`)
+	session := resp.Header.Get(sessionHeader)
+	var init JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&init); err != nil || session == "" {
+		t.Fatalf("initialize failed: %v (session %q)", err, session)
+	}
+	if version := init.Result.(map[string]interface{})["protocolVersion"]; version != "2024-11-05" {
+		t.Fatalf("Expected 2024-11-05, got %v", version)
+	}
+
+	resp = postMCP(t, ts, session, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`)
+	var call struct {
+		Result map[string]interface{} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&call); err != nil {
+		t.Fatalf("Failed to decode tools/call response: %v", err)
+	}
+	if _, ok := call.Result["structuredContent"]; ok {
+		t.Errorf("Expected no structuredContent for a 2024-11-05 session, got %+v", call.Result)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set(sessionHeader, session)
+	req.Header.Set(protocolHeader, "1999-01-01")
+	bad, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unsupported %s header, got %d", protocolHeader, bad.StatusCode)
+	}
+}
+
+// openStream opens the SSE stream of a session and returns the data of its events
+func openStream(t *testing.T, ts *httptest.Server, session string) <-chan string {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, session)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 for the SSE stream, got %d", resp.StatusCode)
+	}
+
+	events := make(chan string, 16)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+			}
+		}
+	}()
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan string) string {
+	t.Helper()
+	select {
+	case data := <-events:
+		return data
+	case <-time.After(2 * time.Second):
+		t.Fatal("No event received on the SSE stream")
+		return ""
+	}
+}
+
+func TestHTTP_SubscriptionsPerSession(t *testing.T) {
+	server, ts := setupHTTP(t)
+	const uri = "quint://holon/use-redis"
+
+	var sessions []string
+	var streams []<-chan string
+	for i := 0; i < 3; i++ {
+		session := initializeHTTP(t, ts)
+		sessions = append(sessions, session)
+		streams = append(streams, openStream(t, ts, session))
+	}
+	for _, session := range sessions[:2] {
+		postMCP(t, ts, session, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"`+uri+`"}}`)
+	}
+	postMCP(t, ts, sessions[1], `{"jsonrpc":"2.0","id":3,"method":"resources/unsubscribe","params":{"uri":"`+uri+`"}}`)
+
+	server.ResourceUpdated(uri)
+	// A broadcast marks the end of what each stream was sent
+	server.Notify("quint/test", nil)
+
+	if got := nextEvent(t, streams[0]); !strings.Contains(got, `"method":"notifications/resources/updated"`) || !strings.Contains(got, uri) {
+		t.Errorf("Expected resources/updated for the subscribed session, got %s", got)
+	}
+	for i, stream := range streams {
+		if got := nextEvent(t, stream); !strings.Contains(got, `"method":"quint/test"`) {
+			t.Errorf("Session %d: expected no other resources/updated, got %s", i, got)
+		}
+	}
+}
+
+func TestHTTP_IdleSessionsExpire(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	handler := server.HTTPHandler("secret").(*httpTransport)
+	handler.idleLimit = 50 * time.Millisecond
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	idle := initializeHTTP(t, ts)
+	postMCP(t, ts, idle, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"quint://context"}}`)
+	streaming := initializeHTTP(t, ts)
+	openStream(t, ts, streaming)
+	handler.mu.Lock()
+	idleSession := handler.sessions[idle].session
+	handler.mu.Unlock()
+
+	time.Sleep(2 * handler.idleLimit)
+	// Expired sessions are swept when a new one is created
+	initializeHTTP(t, ts)
+
+	if resp := postMCP(t, ts, idle, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an expired session, got %d", resp.StatusCode)
+	}
+	if slices.Contains(server.connectedSessions(), idleSession) {
+		t.Error("Expected the expired session and its subscriptions to be dropped")
+	}
+	if resp := postMCP(t, ts, streaming, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected a session with an open stream to stay, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTP_ToolCallsOnOtherProjectsDoNotWait(t *testing.T) {
+	server, ts := setupHTTP(t)
+	_, _, otherDir := setupTools(t)
+	if err := server.AddProject(otherDir); err != nil {
+		t.Fatal(err)
+	}
+	busy, other := initializeHTTP(t, ts), initializeHTTP(t, ts)
+
+	// A long call that changes the server's own project
+	server.tools.callMu.Lock()
+	slow := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_propose","arguments":{`+proposeCacheArgs+`}}}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set(sessionHeader, busy)
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		slow <- err
+	}()
+
+	resp := postMCP(t, ts, other, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_status","arguments":{"project":"`+filepath.Base(otherDir)+`"}}}`)
+	var result JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Error != nil {
+		t.Errorf("Expected the other project's call to complete, got %+v (%v)", result, err)
+	}
+
+	server.tools.callMu.Unlock()
+	if err := <-slow; err != nil {
+		t.Errorf("Expected the held call to finish, got %v", err)
+	}
+}
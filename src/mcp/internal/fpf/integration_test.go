@@ -94,7 +94,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		if fsm.GetPhase() != fpf.PhaseIdle {
 			t.Fatalf("Expected phase IDLE before first proposal, got %s", fsm.GetPhase())
 		}
-		path, err := tools.ProposeHypothesis(hypo1Title, hypo1Content, "global", "system", "Integration Test Rationale", "", nil, 3)
+		path, err := tools.ProposeHypothesis(t.Context(), hypo1Title, hypo1Content, "global", "system", "Integration Test Rationale", "", nil, 3, 0)
 		if err != nil {
 			t.Fatalf("ProposeHypothesis failed: %v", err)
 		}
@@ -124,7 +124,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		evidenceContent := "Deductive logic check passes."
 		verdict := "PASS"
 
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "logic", evidenceContent, verdict, "L1", "logic-carrier", "2025-12-31")
+		evidencePath, err := tools.ManageEvidence(t.Context(), fsm.State.Phase, "add", hypo1ID, "logic", evidenceContent, verdict, "L1", "logic-carrier", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Deduction PASS) failed: %v", err)
 		}
@@ -162,7 +162,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 			t.Fatalf("Hypothesis %s not found in L1 before Induction PASS test", hypo1ID)
 		}
 
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo1ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier", "2025-12-31")
+		evidencePath, err := tools.ManageEvidence(t.Context(), fsm.State.Phase, "add", hypo1ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Induction PASS) failed: %v", err)
 		}
@@ -202,7 +202,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 
 		insight := "New insight from empirical failure."
 
-		childPath, err := tools.RefineLoopback(fsm.State.Phase, loopbackHypoID, insight, hypo2Title, hypo2Content, "system")
+		childPath, err := tools.RefineLoopback(t.Context(), fsm.State.Phase, loopbackHypoID, insight, hypo2Title, hypo2Content, "system")
 		if err != nil {
 			t.Fatalf("RefineLoopback failed: %v", err)
 		}
@@ -241,7 +241,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		verdict := "PASS"
 
 		// hypo2ID is the new child hypothesis, created in L0
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "logic", evidenceContent, verdict, "L1", "logic-carrier-2", "2025-12-31")
+		evidencePath, err := tools.ManageEvidence(t.Context(), fsm.State.Phase, "add", hypo2ID, "logic", evidenceContent, verdict, "L1", "logic-carrier-2", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Deduction PASS for refined) failed: %v", err)
 		}
@@ -280,7 +280,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 		verdict := "PASS"
 
 		// hypo2ID is in L1
-		evidencePath, err := tools.ManageEvidence(fsm.State.Phase, "add", hypo2ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier-2", "2025-12-31")
+		evidencePath, err := tools.ManageEvidence(t.Context(), fsm.State.Phase, "add", hypo2ID, "empirical", evidenceContent, verdict, "L2", "empirical-carrier-2", "2025-12-31")
 		if err != nil {
 			t.Fatalf("ManageEvidence (Induction PASS refined) failed: %v", err)
 		}
@@ -317,7 +317,7 @@ func TestFullFPFWorkflowIntegration(t *testing.T) {
 			t.Fatalf("SaveState failed: %v", err)
 		}
 
-		path, err := tools.FinalizeDecision("Final Decision", finalWinnerID, nil, "Context", "Decision", drrContent, "Consequences", "Characteristics")
+		path, err := tools.FinalizeDecision(t.Context(), "Final Decision", finalWinnerID, nil, "Context", "Decision", drrContent, "Consequences", "Characteristics")
 		if err != nil {
 			t.Fatalf("FinalizeDecision failed: %v", err)
 		}
@@ -0,0 +1,111 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LogLevel is a syslog severity (RFC 5424), as used by MCP logging
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogNotice
+	LogWarning
+	LogError
+	LogCritical
+	LogAlert
+	LogEmergency
+)
+
+var logLevelNames = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+func (l LogLevel) String() string {
+	if l < LogDebug || l > LogEmergency {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return logLevelNames[l]
+}
+
+// ParseLogLevel parses an MCP logging level name
+func ParseLogLevel(name string) (LogLevel, error) {
+	for i, n := range logLevelNames {
+		if n == name {
+			return LogLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected one of %s)", name, strings.Join(logLevelNames, ", "))
+}
+
+// LogSink receives every log message with the context of the request that caused it; it decides
+// which clients see it, e.g. as notifications/message
+type LogSink func(ctx context.Context, level LogLevel, message string)
+
+// Logger writes warnings and errors to stderr and forwards messages to the sink.
+// A nil *Logger only writes to stderr.
+type Logger struct {
+	mu          sync.Mutex
+	stderr      io.Writer
+	stderrLevel LogLevel // Minimum level written to stderr
+	sink        LogSink
+}
+
+// NewLogger creates a logger writing warnings and above to w
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{stderr: w, stderrLevel: LogWarning}
+}
+
+// SetSink sets where client-visible messages go; nil disables forwarding
+func (l *Logger) SetSink(sink LogSink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sink = sink
+}
+
+// The logging methods take the context of the request a message is about, so a server can
+// show it to the client that made the request
+func (l *Logger) Debugf(ctx context.Context, format string, args ...interface{}) {
+	l.logf(ctx, LogDebug, format, args...)
+}
+func (l *Logger) Infof(ctx context.Context, format string, args ...interface{}) {
+	l.logf(ctx, LogInfo, format, args...)
+}
+func (l *Logger) Warnf(ctx context.Context, format string, args ...interface{}) {
+	l.logf(ctx, LogWarning, format, args...)
+}
+func (l *Logger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	l.logf(ctx, LogError, format, args...)
+}
+
+func (l *Logger) logf(ctx context.Context, level LogLevel, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if l == nil {
+		if level >= LogWarning {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", stderrPrefix(level), message)
+		}
+		return
+	}
+
+	l.mu.Lock()
+	if level >= l.stderrLevel {
+		fmt.Fprintf(l.stderr, "%s: %s\n", stderrPrefix(level), message)
+	}
+	sink := l.sink
+	l.mu.Unlock()
+
+	// The sink runs unlocked: it may write to transports that log their own failures
+	if sink != nil {
+		sink(ctx, level, message)
+	}
+}
+
+// stderrPrefix keeps the "Warning: ..." / "Error: ..." format of the stderr output
+func stderrPrefix(level LogLevel) string {
+	name := level.String()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,144 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Notifier is told about knowledge-base changes so connected clients can refresh
+type Notifier interface {
+	ResourceUpdated(uri string)
+	ResourceListChanged()
+}
+
+func holonURI(id string) string    { return holonURIPrefix + id }
+func drrURI(id string) string      { return drrURIPrefix + id }
+func evidenceURI(id string) string { return evidenceURIPrefix + id }
+
+func (t *Tools) resourceUpdated(uris ...string) {
+	if t.Notifier == nil {
+		return
+	}
+	for _, uri := range uris {
+		t.Notifier.ResourceUpdated(uri)
+	}
+}
+
+func (t *Tools) resourceListChanged() {
+	if t.Notifier != nil {
+		t.Notifier.ResourceListChanged()
+	}
+}
+
+// projectNotifier reports the changes of one project, whose resources may be named by their root
+type projectNotifier struct {
+	server *Server
+	root   string
+}
+
+func (n projectNotifier) ResourceUpdated(uri string) { n.server.projectResourceUpdated(n.root, uri) }
+func (n projectNotifier) ResourceListChanged()       { n.server.ResourceListChanged() }
+
+// ResourceUpdated notifies clients subscribed to uri of the server's own project
+func (s *Server) ResourceUpdated(uri string) {
+	s.projectResourceUpdated(s.tools.RootDir, uri)
+}
+
+// projectResourceUpdated notifies the sessions subscribed to a resource of the project at root:
+// by its plain URI if that is the session's default project, and by the URI naming the project
+func (s *Server) projectResourceUpdated(root, uri string) {
+	qualified := projectResourceURI(uri, root)
+	for _, sess := range s.connectedSessions() {
+		for _, u := range []string{uri, qualified} {
+			if u == uri && s.defaultRoot(sess.rootPaths()) != root {
+				continue
+			}
+			if !sess.subscribedTo(u) {
+				continue
+			}
+			if data, ok := notification("notifications/resources/updated", map[string]string{"uri": u}); ok {
+				sess.deliver(data)
+			}
+		}
+	}
+}
+
+// ResourceListChanged tells clients that holons or DRRs were added or moved
+func (s *Server) ResourceListChanged() {
+	s.Notify("notifications/resources/list_changed", nil)
+}
+
+// phaseChanged announces an FSM phase change of the project at root to the sessions working on it:
+// those whose default project it is and those subscribed to one of its resources. Tool preconditions
+// depend on the phase, so these sessions are also told to refresh the tool list.
+func (s *Server) phaseChanged(root string, from, to Phase) {
+	phase, ok := notification("quint/phaseChanged", map[string]string{"project": root, "from": string(from), "to": string(to)})
+	if !ok {
+		return
+	}
+	toolsChanged, ok := notification("notifications/tools/list_changed", nil)
+	if !ok {
+		return
+	}
+	for _, sess := range s.connectedSessions() {
+		if s.defaultRoot(sess.rootPaths()) != root && !sess.subscribedToProject(root) {
+			continue
+		}
+		sess.deliver(phase)
+		sess.deliver(toolsChanged)
+	}
+}
+
+// handleResourcesSubscribe records a subscription of the requesting session; other sessions keep theirs
+func (s *Server) handleResourcesSubscribe(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return errorResponse(req.ID, -32602, "Invalid params: uri is required")
+	}
+	sess := sessionFrom(ctx)
+	if sess == nil {
+		return errorResponse(req.ID, -32603, "resources/subscribe needs a client session")
+	}
+
+	sess.setSubscribed(params.URI, req.Method == "resources/subscribe")
+	s.startSession(sess)
+	return resultResponse(req.ID, map[string]interface{}{})
+}
+
+// notifyScoreChanges reports holons whose cached R differs from before
+func (t *Tools) notifyScoreChanges(ctx context.Context, before map[string]float64) {
+	if t.Notifier == nil {
+		return
+	}
+	after, err := t.cachedScores(ctx)
+	if err != nil {
+		return
+	}
+	for id, score := range after {
+		if old, ok := before[id]; !ok || old != score {
+			t.resourceUpdated(holonURI(id))
+		}
+	}
+}
+
+// cachedScores reads cached_r_score for every holon
+func (t *Tools) cachedScores(ctx context.Context) (map[string]float64, error) {
+	rows, err := t.DB.GetRawDB().QueryContext(ctx, "SELECT id, COALESCE(cached_r_score, 0) FROM holons")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	scores := make(map[string]float64)
+	for rows.Next() {
+		var id string
+		var score float64
+		if err := rows.Scan(&id, &score); err != nil {
+			continue
+		}
+		scores[id] = score
+	}
+	return scores, rows.Err()
+}
@@ -0,0 +1,154 @@
+package fpf
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// captureNotifications subscribes to the server and records the notification methods and params
+func captureNotifications(t *testing.T, s *Server) func() []JSONRPCNotification {
+	record, drain := recordNotifications(t)
+	t.Cleanup(s.subscribe(record))
+	return drain
+}
+
+// recordNotifications returns a message sink and a function returning what it received since the last call
+func recordNotifications(t *testing.T) (func([]byte), func() []JSONRPCNotification) {
+	var mu sync.Mutex
+	var got []JSONRPCNotification
+	record := func(data []byte) {
+		var n JSONRPCNotification
+		if err := json.Unmarshal(data, &n); err != nil {
+			t.Errorf("invalid notification %s: %v", data, err)
+			return
+		}
+		mu.Lock()
+		got = append(got, n)
+		mu.Unlock()
+	}
+	return record, func() []JSONRPCNotification {
+		mu.Lock()
+		defer mu.Unlock()
+		result := got
+		got = nil
+		return result
+	}
+}
+
+func notificationFor(notifications []JSONRPCNotification, method, uri string) bool {
+	for _, n := range notifications {
+		if n.Method != method {
+			continue
+		}
+		if uri == "" {
+			return true
+		}
+		if params, ok := n.Params.(map[string]interface{}); ok && params["uri"] == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNotify_ResourceSubscriptions(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	record, drain := recordNotifications(t)
+	t.Cleanup(server.subscribe(record))
+	ctx := withSession(t.Context(), &session{send: record})
+	fsm.State.Phase = PhaseAbduction
+
+	if _, err := tools.ProposeHypothesis(t.Context(), "Use Redis", "Cache sessions in Redis.", "global", "system", "Fast", "", nil, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	got := drain()
+	if !notificationFor(got, "notifications/resources/list_changed", "") {
+		t.Errorf("Expected list_changed after propose, got %+v", got)
+	}
+
+	// Unsubscribed URIs are not announced
+	if _, err := tools.MoveHypothesis(t.Context(), "use-redis", "L0", "L1"); err != nil {
+		t.Fatalf("MoveHypothesis failed: %v", err)
+	}
+	if got := drain(); notificationFor(got, "notifications/resources/updated", "quint://holon/use-redis") {
+		t.Errorf("Did not expect resources/updated without a subscription, got %+v", got)
+	}
+
+	resp := server.Handle(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "resources/subscribe", Params: json.RawMessage(`{"uri":"quint://holon/use-redis"}`)})
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("resources/subscribe failed: %+v", resp)
+	}
+
+	if _, err := tools.ManageEvidence(t.Context(), PhaseInduction, "add", "use-redis", "internal", "Load test ok", "pass", "L1", "", ""); err != nil {
+		t.Fatalf("ManageEvidence failed: %v", err)
+	}
+	if got := drain(); !notificationFor(got, "notifications/resources/updated", "quint://holon/use-redis") {
+		t.Errorf("Expected resources/updated for the subscribed holon, got %+v", got)
+	}
+
+	server.Handle(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "resources/unsubscribe", Params: json.RawMessage(`{"uri":"quint://holon/use-redis"}`)})
+	if _, err := tools.MoveHypothesis(t.Context(), "use-redis", "L1", "invalid"); err != nil {
+		t.Fatalf("MoveHypothesis failed: %v", err)
+	}
+	if got := drain(); notificationFor(got, "notifications/resources/updated", "quint://holon/use-redis") {
+		t.Errorf("Did not expect resources/updated after unsubscribe, got %+v", got)
+	}
+
+	resp = server.Handle(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "resources/subscribe", Params: json.RawMessage(`{}`)})
+	if resp == nil || resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("Expected -32602 for subscribe without uri, got %+v", resp)
+	}
+}
+
+func TestNotify_PhaseChanged(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	_, _, otherDir := setupTools(t)
+	server := NewServer(tools)
+
+	// own works on the server's project, other on another one, follower on another one
+	// but subscribed to a resource of the server's project
+	connect := func(roots ...string) (*session, func() []JSONRPCNotification) {
+		record, drain := recordNotifications(t)
+		sess := &session{send: record}
+		sess.setRoots(roots)
+		server.startSession(sess)
+		t.Cleanup(func() { server.endSession(sess) })
+		return sess, drain
+	}
+	_, own := connect()
+	_, other := connect(otherDir)
+	follower, following := connect(otherDir)
+	follower.setSubscribed(projectResourceURI(holonURI("cache"), tools.RootDir), true)
+
+	fsm.State.Phase = PhaseAbduction
+	if err := fsm.SaveState("default"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	for name, drain := range map[string]func() []JSONRPCNotification{"own": own, "follower": following} {
+		got := drain()
+		var phase map[string]interface{}
+		for _, n := range got {
+			if n.Method == "quint/phaseChanged" {
+				phase, _ = n.Params.(map[string]interface{})
+			}
+		}
+		if phase["project"] != tools.RootDir || phase["from"] != string(PhaseIdle) || phase["to"] != string(PhaseAbduction) {
+			t.Errorf("Expected %s to get quint/phaseChanged IDLE -> ABDUCTION of %s, got %+v", name, tools.RootDir, got)
+		}
+		if !notificationFor(got, "notifications/tools/list_changed", "") {
+			t.Errorf("Expected %s to get tools/list_changed with the phase change, got %+v", name, got)
+		}
+	}
+	if got := other(); len(got) != 0 {
+		t.Errorf("Expected no phase change for a session on another project, got %+v", got)
+	}
+
+	// Saving the same phase again is not a change
+	if err := fsm.SaveState("default"); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	if got := own(); len(got) != 0 {
+		t.Errorf("Expected no notifications for an unchanged phase, got %+v", got)
+	}
+}
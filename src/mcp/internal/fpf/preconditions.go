@@ -17,28 +17,32 @@ func (e *PreconditionError) Error() string {
 	return fmt.Sprintf("Precondition failed for %s: %s. Suggestion: %s", e.Tool, e.Condition, e.Suggestion)
 }
 
-func (t *Tools) CheckPreconditions(toolName string, args map[string]string) error {
+func (t *Tools) CheckPreconditions(ctx context.Context, toolName string, args map[string]string) error {
 	switch toolName {
 	case "quint_propose":
-		return t.checkProposePreconditions(args)
+		return t.checkProposePreconditions(ctx, args)
 	case "quint_verify":
-		return t.checkVerifyPreconditions(args)
+		return t.checkVerifyPreconditions(ctx, args)
 	case "quint_test":
-		return t.checkTestPreconditions(args)
+		return t.checkTestPreconditions(ctx, args)
 	case "quint_audit":
-		return t.checkAuditPreconditions(args)
+		return t.checkAuditPreconditions(ctx, args)
 	case "quint_decide":
-		return t.checkDecidePreconditions(args)
+		return t.checkDecidePreconditions(ctx, args)
 	case "quint_calculate_r":
-		return t.checkCalculateRPreconditions(args)
+		return t.checkCalculateRPreconditions(ctx, args)
 	case "quint_audit_tree":
-		return t.checkAuditTreePreconditions(args)
+		return t.checkAuditTreePreconditions(ctx, args)
+	case "quint_check_scope":
+		return t.checkCheckScopePreconditions(ctx, args)
+	case "quint_what_if":
+		return t.checkWhatIfPreconditions(ctx, args)
 	default:
 		return nil
 	}
 }
 
-func (t *Tools) checkProposePreconditions(args map[string]string) error {
+func (t *Tools) checkProposePreconditions(ctx context.Context, args map[string]string) error {
 	if args["title"] == "" {
 		return &PreconditionError{
 			Tool:       "quint_propose",
@@ -63,7 +67,7 @@ func (t *Tools) checkProposePreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkVerifyPreconditions(args map[string]string) error {
+func (t *Tools) checkVerifyPreconditions(ctx context.Context, args map[string]string) error {
 	hypoID := args["hypothesis_id"]
 	if hypoID == "" {
 		return &PreconditionError{
@@ -94,7 +98,7 @@ func (t *Tools) checkVerifyPreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkTestPreconditions(args map[string]string) error {
+func (t *Tools) checkTestPreconditions(ctx context.Context, args map[string]string) error {
 	hypoID := args["hypothesis_id"]
 	if hypoID == "" {
 		return &PreconditionError{
@@ -127,7 +131,6 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 
 	if !l1Exists && !l2Exists {
 		if t.DB != nil {
-			ctx := context.Background()
 			holon, err := t.DB.GetHolon(ctx, hypoID)
 			if err != nil || (holon.Layer != "L1" && holon.Layer != "L2") {
 				return &PreconditionError{
@@ -157,7 +160,7 @@ func (t *Tools) checkTestPreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkAuditPreconditions(args map[string]string) error {
+func (t *Tools) checkAuditPreconditions(ctx context.Context, args map[string]string) error {
 	hypoID := args["hypothesis_id"]
 	if hypoID == "" {
 		return &PreconditionError{
@@ -168,7 +171,6 @@ func (t *Tools) checkAuditPreconditions(args map[string]string) error {
 	}
 
 	if t.DB != nil {
-		ctx := context.Background()
 		holon, err := t.DB.GetHolon(ctx, hypoID)
 		if err != nil {
 			return &PreconditionError{
@@ -189,7 +191,7 @@ func (t *Tools) checkAuditPreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkDecidePreconditions(args map[string]string) error {
+func (t *Tools) checkDecidePreconditions(ctx context.Context, args map[string]string) error {
 	winnerID := args["winner_id"]
 	if winnerID == "" {
 		return &PreconditionError{
@@ -208,7 +210,6 @@ func (t *Tools) checkDecidePreconditions(args map[string]string) error {
 	}
 
 	if t.DB != nil {
-		ctx := context.Background()
 		counts, _ := t.DB.CountHolonsByLayer(ctx, "default")
 
 		l2Count := int64(0)
@@ -231,7 +232,7 @@ func (t *Tools) checkDecidePreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkCalculateRPreconditions(args map[string]string) error {
+func (t *Tools) checkCalculateRPreconditions(ctx context.Context, args map[string]string) error {
 	if t.DB == nil {
 		return &PreconditionError{
 			Tool:       "quint_calculate_r",
@@ -249,7 +250,6 @@ func (t *Tools) checkCalculateRPreconditions(args map[string]string) error {
 		}
 	}
 
-	ctx := context.Background()
 	_, err := t.DB.GetHolon(ctx, holonID)
 	if err != nil {
 		return &PreconditionError{
@@ -262,7 +262,7 @@ func (t *Tools) checkCalculateRPreconditions(args map[string]string) error {
 	return nil
 }
 
-func (t *Tools) checkAuditTreePreconditions(args map[string]string) error {
+func (t *Tools) checkAuditTreePreconditions(ctx context.Context, args map[string]string) error {
 	if t.DB == nil {
 		return &PreconditionError{
 			Tool:       "quint_audit_tree",
@@ -282,3 +282,69 @@ func (t *Tools) checkAuditTreePreconditions(args map[string]string) error {
 
 	return nil
 }
+
+func (t *Tools) checkCheckScopePreconditions(ctx context.Context, args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_check_scope",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	holonID := args["holon_id"]
+	if holonID == "" {
+		return &PreconditionError{
+			Tool:       "quint_check_scope",
+			Condition:  "holon_id is required",
+			Suggestion: "Specify which holon or decision to check",
+		}
+	}
+
+	if args["slice"] == "" {
+		return &PreconditionError{
+			Tool:       "quint_check_scope",
+			Condition:  "slice is required",
+			Suggestion: "Describe the context slice, e.g. 'service=payments; env=prod'",
+		}
+	}
+
+	if _, err := t.DB.GetHolon(ctx, holonID); err != nil {
+		return &PreconditionError{
+			Tool:       "quint_check_scope",
+			Condition:  fmt.Sprintf("holon '%s' not found", holonID),
+			Suggestion: "Ensure the holon exists in the database",
+		}
+	}
+
+	return nil
+}
+
+func (t *Tools) checkWhatIfPreconditions(ctx context.Context, args map[string]string) error {
+	if t.DB == nil {
+		return &PreconditionError{
+			Tool:       "quint_what_if",
+			Condition:  "database not initialized",
+			Suggestion: "Run /q0-init to initialize the project first",
+		}
+	}
+
+	holonID := args["holon_id"]
+	if holonID == "" {
+		return &PreconditionError{
+			Tool:       "quint_what_if",
+			Condition:  "holon_id is required",
+			Suggestion: "Specify which holon to simulate changes for",
+		}
+	}
+
+	if _, err := t.DB.GetHolon(ctx, holonID); err != nil {
+		return &PreconditionError{
+			Tool:       "quint_what_if",
+			Condition:  fmt.Sprintf("holon '%s' not found", holonID),
+			Suggestion: "Ensure the holon exists in the database",
+		}
+	}
+
+	return nil
+}
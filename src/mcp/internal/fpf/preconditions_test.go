@@ -62,7 +62,7 @@ func TestCheckPreconditions_Propose(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tools.CheckPreconditions("quint_propose", tt.args)
+			err := tools.CheckPreconditions(t.Context(), "quint_propose", tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -123,7 +123,7 @@ func TestCheckPreconditions_Verify(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tools.CheckPreconditions("quint_verify", tt.args)
+			err := tools.CheckPreconditions(t.Context(), "quint_verify", tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -184,7 +184,7 @@ func TestCheckPreconditions_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tools.CheckPreconditions("quint_test", tt.args)
+			err := tools.CheckPreconditions(t.Context(), "quint_test", tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -254,7 +254,7 @@ func TestCheckPreconditions_Decide(t *testing.T) {
 			if tt.setup != nil {
 				tt.setup()
 			}
-			err := tools.CheckPreconditions("quint_decide", tt.args)
+			err := tools.CheckPreconditions(t.Context(), "quint_decide", tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -304,7 +304,7 @@ func TestCheckPreconditions_CalculateR(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tools.CheckPreconditions("quint_calculate_r", tt.args)
+			err := tools.CheckPreconditions(t.Context(), "quint_calculate_r", tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CheckPreconditions() error = %v, wantErr %v", err, tt.wantErr)
 			}
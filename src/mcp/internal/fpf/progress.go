@@ -0,0 +1,28 @@
+package fpf
+
+import "context"
+
+// ProgressFunc receives progress updates from a long-running tool. total is 0 when unknown.
+type ProgressFunc func(progress, total float64, message string)
+
+type progressKey struct{}
+
+// progressParams is the payload of notifications/progress
+type progressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// WithProgress returns a context whose tool calls report progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress forwards a progress update to the ProgressFunc on ctx, if any
+func reportProgress(ctx context.Context, progress, total float64, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(progress, total, message)
+	}
+}
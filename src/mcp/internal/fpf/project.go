@@ -0,0 +1,215 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// rootsTimeout bounds how long the server waits for the client to answer roots/list
+const rootsTimeout = 10 * time.Second
+
+// OpenProject opens the Quint project rooted at root: its database (if .quint/quint.db exists),
+// FSM state and assurance policy. A project without a database can still be initialized with quint_init.
+func OpenProject(root string) (*Tools, error) {
+	quintDir := filepath.Join(root, ".quint")
+	dbPath := filepath.Join(quintDir, "quint.db")
+	log := NewLogger(os.Stderr)
+
+	var database *db.Store
+	if _, err := os.Stat(dbPath); err == nil {
+		database, err = db.NewStore(dbPath)
+		if err != nil {
+			log.Warnf(context.Background(), "failed to open database: %v", err)
+		}
+	}
+
+	var rawDB *sql.DB
+	if database != nil {
+		rawDB = database.GetRawDB()
+	}
+
+	fsm, err := LoadState("default", rawDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	fsm.PolicyDir = quintDir
+	if _, err := fsm.AssurancePolicy(); err != nil {
+		log.Warnf(context.Background(), "%v (assurance tools and the Operation gate fail until it is fixed)", err)
+	}
+
+	return NewTools(fsm, root, database), nil
+}
+
+// AddProject allows tool calls to select the project rooted at root with the project argument.
+// The project is opened on first use.
+func (s *Server) AddProject(root string) error {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	s.projectsMu.Lock()
+	defer s.projectsMu.Unlock()
+	if !slices.Contains(s.projectRoots, abs) {
+		s.projectRoots = append(s.projectRoots, abs)
+	}
+	return nil
+}
+
+// attach makes the server the change notifier of a project and shares its logger
+func (s *Server) attach(t *Tools) {
+	t.Notifier = projectNotifier{server: s, root: t.RootDir}
+	t.ReadOnly = s.readOnly
+	if t.FSM != nil {
+		root := t.RootDir
+		t.FSM.OnPhaseChange = func(from, to Phase) { s.phaseChanged(root, from, to) }
+	}
+	if s.tools != nil && s.tools != t {
+		t.Log = s.tools.Log
+	}
+}
+
+// projectFor resolves the project a request works on. An empty name selects the client's root when
+// it announced exactly one and that root is an initialized Quint project, and the server's own
+// project otherwise. A name is a project root path or its directory name, and must be a configured
+// project or a client root.
+func (s *Server) projectFor(ctx context.Context, name string) (*Tools, error) {
+	var clientRoots []string
+	if sess := sessionFrom(ctx); sess != nil {
+		clientRoots = sess.rootPaths()
+	}
+
+	if name == "" {
+		return s.openProject(s.defaultRoot(clientRoots))
+	}
+
+	s.projectsMu.Lock()
+	candidates := append([]string{s.tools.RootDir}, s.projectRoots...)
+	s.projectsMu.Unlock()
+	candidates = append(candidates, clientRoots...)
+
+	var matches []string
+	for _, root := range candidates {
+		if (root == filepath.Clean(name) || filepath.Base(root) == name) && !slices.Contains(matches, root) {
+			matches = append(matches, root)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown project %q (available: %s)", name, strings.Join(candidates, ", "))
+	case 1:
+		return s.openProject(matches[0])
+	default:
+		return nil, fmt.Errorf("project %q is ambiguous, use its path: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// defaultRoot returns the root of the project a session with the given client roots works on
+// when it names none
+func (s *Server) defaultRoot(clientRoots []string) string {
+	if len(clientRoots) == 1 && clientRoots[0] != s.tools.RootDir && isProject(clientRoots[0]) {
+		return clientRoots[0]
+	}
+	return s.tools.RootDir
+}
+
+// otherProjects returns the initialized projects a request can select besides the one at root:
+// the server's own project, the configured projects and the client's roots
+func (s *Server) otherProjects(ctx context.Context, root string) []string {
+	s.projectsMu.Lock()
+	candidates := append([]string{s.tools.RootDir}, s.projectRoots...)
+	s.projectsMu.Unlock()
+	if sess := sessionFrom(ctx); sess != nil {
+		candidates = append(candidates, sess.rootPaths()...)
+	}
+
+	var roots []string
+	for _, candidate := range candidates {
+		if candidate != root && !slices.Contains(roots, candidate) && isProject(candidate) {
+			roots = append(roots, candidate)
+		}
+	}
+	return roots
+}
+
+// isProject reports whether root holds an initialized Quint project
+func isProject(root string) bool {
+	_, err := os.Stat(filepath.Join(root, ".quint", "quint.db"))
+	return err == nil
+}
+
+// openProject returns the open project at root, opening it on first use
+func (s *Server) openProject(root string) (*Tools, error) {
+	if root == s.tools.RootDir {
+		return s.tools, nil
+	}
+	s.projectsMu.Lock()
+	defer s.projectsMu.Unlock()
+	if t, ok := s.projects[root]; ok {
+		return t, nil
+	}
+	t, err := OpenProject(root)
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", root, err)
+	}
+	s.attach(t)
+	s.projects[root] = t
+	return t, nil
+}
+
+// refreshRoots asks the client for its roots, if it supports them and can receive server requests,
+// and records them on the session. The answer arrives through the same transport, so it runs in the background.
+func (s *Server) refreshRoots(ctx context.Context) {
+	sess := sessionFrom(ctx)
+	if sess == nil || !sess.hasCapability("roots") || !sess.canReceive() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rootsTimeout)
+	go func() {
+		defer cancel()
+		result, err := s.request(ctx, sess, "roots/list", nil)
+		if err != nil {
+			s.tools.Log.Warnf(ctx, "roots/list failed: %v", err)
+			return
+		}
+		roots, err := parseRoots(result)
+		if err != nil {
+			s.tools.Log.Warnf(ctx, "invalid roots/list result: %v", err)
+			return
+		}
+		sess.setRoots(roots)
+		s.tools.Log.Infof(ctx, "client roots: %s", strings.Join(roots, ", "))
+	}()
+}
+
+// parseRoots returns the local paths of the file:// roots in a roots/list result
+func parseRoots(result json.RawMessage) ([]string, error) {
+	var list struct {
+		Roots []struct {
+			URI  string `json:"uri"`
+			Name string `json:"name"`
+		} `json:"roots"`
+	}
+	if err := json.Unmarshal(result, &list); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, root := range list.Roots {
+		u, err := url.Parse(root.URI)
+		if err != nil || u.Scheme != "file" || u.Path == "" {
+			continue
+		}
+		paths = append(paths, filepath.Clean(filepath.FromSlash(u.Path)))
+	}
+	return paths, nil
+}
@@ -0,0 +1,162 @@
+package fpf
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const proposeCacheArgs = `"title":"Cache","content":"c","scope":"global","kind":"system","rationale":"{}"`
+
+func TestToolsCall_ProjectArgument(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	other, _, otherDir := setupTools(t)
+	server := NewServer(tools)
+	if err := server.AddProject(otherDir); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(id int, arguments string) *JSONRPCResponse {
+		return server.Handle(t.Context(), JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      id,
+			Method:  "tools/call",
+			Params:  json.RawMessage(`{"name":"quint_propose","arguments":{` + arguments + `}}`),
+		})
+	}
+
+	if resp := call(1, proposeCacheArgs+`,"project":"`+filepath.Base(otherDir)+`"`); resp.Error != nil {
+		t.Fatalf("tools/call failed: %+v", resp.Error)
+	}
+	if _, err := other.DB.GetHolon(t.Context(), "cache"); err != nil {
+		t.Errorf("Expected the holon in the selected project: %v", err)
+	}
+	if _, err := tools.DB.GetHolon(t.Context(), "cache"); err == nil {
+		t.Error("Expected the server's own project to be untouched")
+	}
+
+	countWork := func(tools *Tools) int {
+		var n int
+		if err := tools.DB.GetRawDB().QueryRow(`SELECT COUNT(*) FROM work_records WHERE method_ref = 'ProposeHypothesis'`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if countWork(other) != 1 || countWork(tools) != 0 {
+		t.Errorf("Expected the work record only in the selected project, got %d and %d", countWork(other), countWork(tools))
+	}
+
+	resp := call(2, proposeCacheArgs+`,"project":"/not/a/project"`)
+	if resp.Error == nil || resp.Error.Code != -32602 || !strings.Contains(resp.Error.Message, "unknown project") {
+		t.Errorf("Expected -32602 for an unknown project, got %+v", resp)
+	}
+}
+
+func TestServe_ClientRootsSelectProject(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	other, _, otherDir := setupTools(t)
+
+	out := proposeWithClientRoot(t, NewServer(tools), otherDir)
+	if resp := out.responses(t)["2"]; resp.Error != nil {
+		t.Fatalf("tools/call failed: %+v", resp.Error)
+	}
+	if _, err := other.DB.GetHolon(t.Context(), "cache"); err != nil {
+		t.Errorf("Expected the holon in the client's only root: %v", err)
+	}
+}
+
+func TestServe_ClientRootWithoutProject(t *testing.T) {
+	tools, _, _ := setupTools(t)
+
+	out := proposeWithClientRoot(t, NewServer(tools), t.TempDir())
+	if resp := out.responses(t)["2"]; resp.Error != nil {
+		t.Fatalf("tools/call failed: %+v", resp.Error)
+	}
+	if _, err := tools.DB.GetHolon(t.Context(), "cache"); err != nil {
+		t.Errorf("Expected the holon in the server's own project when the client root has no .quint: %v", err)
+	}
+}
+
+// proposeWithClientRoot serves a client announcing root as its only root and proposes a holon
+// without naming a project
+func proposeWithClientRoot(t *testing.T, server *Server, root string) *syncBuffer {
+	t.Helper()
+	in, w := io.Pipe()
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(t.Context(), in, out) }()
+
+	io.WriteString(w, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"roots":{"listChanged":true}}}}`+"\n")
+	waitFor(t, "initialize response", func() bool {
+		_, ok := out.responses(t)["1"]
+		return ok
+	})
+	io.WriteString(w, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	waitFor(t, "roots/list request", func() bool {
+		return strings.Contains(out.String(), `"method":"roots/list"`)
+	})
+
+	io.WriteString(w, `{"jsonrpc":"2.0","id":"quint-1","result":{"roots":[{"uri":"file://`+filepath.ToSlash(root)+`","name":"other"}]}}`+"\n")
+	waitFor(t, "roots to be recorded", func() bool {
+		return strings.Contains(out.String(), "client roots: "+root)
+	})
+
+	io.WriteString(w, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"quint_propose","arguments":{`+proposeCacheArgs+`}}}`+"\n")
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	return out
+}
+
+func TestResources_OtherProjects(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	other, _, otherDir := setupTools(t)
+	server := NewServer(tools)
+	if err := server.AddProject(otherDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.DB.CreateHolon(t.Context(), "cache", "hypothesis", "system", "L0", "Cache", "Cache sessions elsewhere.", "default", "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	uri := projectResourceURI("quint://holon/cache", otherDir)
+
+	resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "resources/list"})
+	if resp.Error != nil {
+		t.Fatalf("resources/list failed: %+v", resp.Error)
+	}
+	data, _ := json.Marshal(resp.Result)
+	if !strings.Contains(string(data), `"uri":"`+uri+`"`) {
+		t.Errorf("Expected %s in resources/list, got %s", uri, data)
+	}
+
+	resp = server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "resources/read", Params: json.RawMessage(`{"uri":"` + uri + `"}`)})
+	if resp.Error != nil {
+		t.Fatalf("resources/read failed: %+v", resp.Error)
+	}
+	data, _ = json.Marshal(resp.Result)
+	if !strings.Contains(string(data), "Cache sessions elsewhere.") || !strings.Contains(string(data), `"uri":"`+uri+`"`) {
+		t.Errorf("Expected the holon of the other project, got %s", data)
+	}
+
+	resp = server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "resources/read", Params: json.RawMessage(`{"uri":"quint://holon/cache"}`)})
+	if resp.Error == nil || resp.Error.Code != -32002 {
+		t.Errorf("Expected the plain URI to read the server's own project, got %+v", resp)
+	}
+
+	record, drain := recordNotifications(t)
+	ctx := withSession(t.Context(), &session{send: record})
+	server.Handle(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 4, Method: "resources/subscribe", Params: json.RawMessage(`{"uri":"` + uri + `"}`)})
+	opened, err := server.openProject(otherDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened.resourceUpdated(holonURI("cache"))
+	tools.resourceUpdated(holonURI("cache"))
+	got := drain()
+	if len(got) != 1 || !notificationFor(got, "notifications/resources/updated", uri) {
+		t.Errorf("Expected one resources/updated for the project-qualified URI, got %+v", got)
+	}
+}
@@ -88,7 +88,7 @@ func ValidateFile(path string) (content string, tampered bool, expectedHash stri
 	return content, false, expectedHash, actualHash, nil
 }
 
-func (t *Tools) ReadWithValidation(path string) (string, *TamperingEvent, error) {
+func (t *Tools) ReadWithValidation(ctx context.Context, path string) (string, *TamperingEvent, error) {
 	content, tampered, expectedHash, actualHash, err := ValidateFile(path)
 	if err != nil {
 		return "", nil, err
@@ -105,7 +105,7 @@ func (t *Tools) ReadWithValidation(path string) (string, *TamperingEvent, error)
 		Regenerated:  false,
 	}
 
-	t.AuditLog("projection_validate", "tampering_detected", "system", path, "ALERT", map[string]string{
+	t.AuditLog(ctx, "projection_validate", "tampering_detected", "system", path, "ALERT", map[string]string{
 		"expected_hash": expectedHash,
 		"actual_hash":   actualHash,
 	}, "Content hash mismatch detected")
@@ -113,10 +113,10 @@ func (t *Tools) ReadWithValidation(path string) (string, *TamperingEvent, error)
 	if t.DB != nil {
 		regenerated, regErr := t.regenerateFromDB(path)
 		if regErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to regenerate %s from DB: %v\n", path, regErr)
+			t.Log.Warnf(ctx, "failed to regenerate %s from DB: %v", path, regErr)
 		} else if regenerated {
 			event.Regenerated = true
-			t.AuditLog("projection_validate", "file_regenerated", "system", path, "SUCCESS", nil, "File regenerated from database")
+			t.AuditLog(ctx, "projection_validate", "file_regenerated", "system", path, "SUCCESS", nil, "File regenerated from database")
 			newContent, _, _, _, _ := ValidateFile(path)
 			return newContent, event, nil
 		}
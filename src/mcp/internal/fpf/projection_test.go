@@ -196,7 +196,7 @@ func TestReadWithValidation_Tampered(t *testing.T) {
 		t.Fatalf("Failed to tamper file: %v", err)
 	}
 
-	_, event, err := tools.ReadWithValidation(path)
+	_, event, err := tools.ReadWithValidation(t.Context(), path)
 	if err != nil {
 		t.Fatalf("ReadWithValidation failed: %v", err)
 	}
@@ -0,0 +1,129 @@
+package fpf
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string      `json:"role"`
+	Content ContentItem `json:"content"`
+}
+
+// ErrPromptNotFound is returned by PromptLibrary.Get for unknown prompt names
+type ErrPromptNotFound struct {
+	Name string
+}
+
+func (e *ErrPromptNotFound) Error() string {
+	return fmt.Sprintf("prompt not found: %s", e.Name)
+}
+
+// promptArgument is the single free-form argument every command accepts.
+// It replaces $ARGUMENTS; $1..$9 are its whitespace-separated words.
+const promptArgument = "arguments"
+
+var (
+	descriptionRegex = regexp.MustCompile(`(?m)^description:\s*"?(.*?)"?\s*$`)
+	positionalRegex  = regexp.MustCompile(`\$([1-9])`)
+)
+
+// PromptLibrary serves the slash-command templates (q0-init … q5-decide) as MCP prompts
+type PromptLibrary struct {
+	fsys fs.FS
+}
+
+// NewPromptLibrary reads *.md command templates from the root of fsys
+func NewPromptLibrary(fsys fs.FS) *PromptLibrary {
+	return &PromptLibrary{fsys: fsys}
+}
+
+// List returns every command template, sorted by name
+func (l *PromptLibrary) List() ([]Prompt, error) {
+	names, err := fs.Glob(l.fsys, "*.md")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	prompts := make([]Prompt, 0, len(names))
+	for _, file := range names {
+		data, err := fs.ReadFile(l.fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		description, _ := splitCommand(string(data))
+		prompts = append(prompts, Prompt{
+			Name:        strings.TrimSuffix(file, ".md"),
+			Description: description,
+			Arguments: []PromptArgument{{
+				Name:        promptArgument,
+				Description: "Free-form input for the command: the problem, hypothesis ID or decision to work on",
+			}},
+		})
+	}
+	return prompts, nil
+}
+
+// Get renders a command template with its arguments substituted. Templates without a
+// $ARGUMENTS placeholder get the input appended as a "User Input" section.
+func (l *PromptLibrary) Get(name string, args map[string]string) (string, []PromptMessage, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", nil, &ErrPromptNotFound{Name: name}
+	}
+	data, err := fs.ReadFile(l.fsys, name+".md")
+	if err != nil {
+		return "", nil, &ErrPromptNotFound{Name: name}
+	}
+	description, body := splitCommand(string(data))
+
+	input := strings.TrimSpace(args[promptArgument])
+	words := strings.Fields(input)
+	text := strings.ReplaceAll(body, "$ARGUMENTS", input)
+	text = positionalRegex.ReplaceAllStringFunc(text, func(m string) string {
+		i := int(m[1] - '1')
+		if i < len(words) {
+			return words[i]
+		}
+		return ""
+	})
+	if input != "" && !strings.Contains(body, "$ARGUMENTS") {
+		text = strings.TrimRight(text, "\n") + "\n\n## User Input\n\n" + input + "\n"
+	}
+
+	return description, []PromptMessage{{
+		Role:    "user",
+		Content: ContentItem{Type: "text", Text: text},
+	}}, nil
+}
+
+// splitCommand separates a command template into its description and body
+func splitCommand(content string) (string, string) {
+	frontmatter, body, ok := parseFrontmatter(content)
+	if ok {
+		if m := descriptionRegex.FindStringSubmatch(frontmatter); m != nil && m[1] != "" {
+			return m[1], strings.TrimLeft(body, "\n")
+		}
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "# ")), strings.TrimLeft(body, "\n")
+		}
+	}
+	return "", strings.TrimLeft(body, "\n")
+}
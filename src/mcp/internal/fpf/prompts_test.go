@@ -0,0 +1,64 @@
+package fpf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testPromptLibrary() *PromptLibrary {
+	return NewPromptLibrary(fstest.MapFS{
+		"q1-hypothesize.md": {Data: []byte("---\ndescription: \"Generate Hypotheses (Abduction)\"\npre: \"none\"\n---\n\n# Phase 1: Abduction\n\nProblem: $ARGUMENTS\nFirst word: $1\n")},
+		"q-decay.md":        {Data: []byte("# q-decay: Evidence Freshness Management\n\nCheck freshness.\n")},
+		"notes.txt":         {Data: []byte("not a command")},
+	})
+}
+
+func TestPromptLibrary_List(t *testing.T) {
+	prompts, err := testPromptLibrary().List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("Expected 2 prompts, got %+v", prompts)
+	}
+	if prompts[0].Name != "q-decay" || prompts[0].Description != "q-decay: Evidence Freshness Management" {
+		t.Errorf("Expected heading as description, got %+v", prompts[0])
+	}
+	if prompts[1].Name != "q1-hypothesize" || prompts[1].Description != "Generate Hypotheses (Abduction)" {
+		t.Errorf("Expected frontmatter description, got %+v", prompts[1])
+	}
+}
+
+func TestPromptLibrary_Get(t *testing.T) {
+	lib := testPromptLibrary()
+
+	description, messages, err := lib.Get("q1-hypothesize", map[string]string{"arguments": "redis cache latency"})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if description != "Generate Hypotheses (Abduction)" || len(messages) != 1 || messages[0].Role != "user" {
+		t.Fatalf("Unexpected prompt: %q %+v", description, messages)
+	}
+	text := messages[0].Content.Text
+	if strings.Contains(text, "pre:") {
+		t.Errorf("Frontmatter leaked into prompt: %s", text)
+	}
+	if !strings.Contains(text, "Problem: redis cache latency") || !strings.Contains(text, "First word: redis") {
+		t.Errorf("Arguments not substituted: %s", text)
+	}
+
+	_, messages, _ = lib.Get("q-decay", map[string]string{"arguments": "focus on auth"})
+	if !strings.HasSuffix(messages[0].Content.Text, "## User Input\n\nfocus on auth\n") {
+		t.Errorf("Expected input appended, got: %s", messages[0].Content.Text)
+	}
+
+	for _, name := range []string{"missing", "../q-decay", ""} {
+		_, _, err := lib.Get(name, nil)
+		var notFound *ErrPromptNotFound
+		if !errors.As(err, &notFound) {
+			t.Errorf("%q: expected ErrPromptNotFound, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// supportedProtocolVersions lists the MCP revisions the server speaks, newest first
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Protocol revisions that introduced features the server gates on
+const (
+	structuredOutputSince = "2025-06-18" // Tool outputSchema and structuredContent
+	progressMessageSince  = "2025-03-26" // message field of notifications/progress
+	annotationsSince      = "2025-03-26" // Tool annotations
+)
+
+// ErrUnsupportedProtocol is returned by initialize when the client only speaks revisions older than the server supports
+type ErrUnsupportedProtocol struct {
+	Requested string
+}
+
+func (e *ErrUnsupportedProtocol) Error() string {
+	return "Unsupported protocol version: " + e.Requested
+}
+
+// negotiateProtocol picks the revision to speak with a client that requested the given one.
+// A supported revision is accepted as is. A newer or unknown revision is downgraded to the
+// newest supported one that is not newer than the request; the client decides whether it can
+// work with it. Revisions older than every supported one are rejected. An empty request is
+// treated as the newest revision.
+func negotiateProtocol(requested string) (string, error) {
+	if requested == "" {
+		return supportedProtocolVersions[0], nil
+	}
+	if _, err := time.Parse(time.DateOnly, requested); err != nil {
+		return "", &ErrUnsupportedProtocol{Requested: requested}
+	}
+	for _, version := range supportedProtocolVersions {
+		if version <= requested {
+			return version, nil
+		}
+	}
+	return "", &ErrUnsupportedProtocol{Requested: requested}
+}
+
+// session is the state of one client connection: the negotiated protocol revision,
+// the capabilities the client announced on initialize and the roots it works in
+type session struct {
+	mu                 sync.Mutex
+	protocolVersion    string
+	clientCapabilities map[string]json.RawMessage
+	clientInfo         map[string]interface{}
+	roots              []string          // Local paths from roots/list
+	send               func(data []byte) // Delivers server requests to the client; nil when it cannot receive them
+	subscriptions      map[string]bool   // Resource URIs the client asked to be notified about
+	logLevel           LogLevel          // Minimum level of notifications/message, set by logging/setLevel
+	logLevelSet        bool              // The client chose logLevel and receives messages of other sessions
+}
+
+type sessionKey struct{}
+
+// withSession returns a context whose requests belong to the client session sess
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFrom returns the client session of a request, or nil outside a transport
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+func (s *session) initialize(version string, capabilities map[string]json.RawMessage, info map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocolVersion = version
+	s.clientCapabilities = capabilities
+	s.clientInfo = info
+}
+
+func (s *session) hasCapability(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.clientCapabilities[name]
+	return ok
+}
+
+func (s *session) setSender(send func(data []byte)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.send = send
+}
+
+func (s *session) canReceive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.send != nil
+}
+
+// deliver sends a message to the client if it can receive one
+func (s *session) deliver(data []byte) {
+	s.mu.Lock()
+	send := s.send
+	s.mu.Unlock()
+	if send != nil {
+		send(data)
+	}
+}
+
+// setSubscribed records a resources/subscribe or resources/unsubscribe
+func (s *session) setSubscribed(uri string, subscribed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if subscribed {
+		if s.subscriptions == nil {
+			s.subscriptions = make(map[string]bool)
+		}
+		s.subscriptions[uri] = true
+	} else {
+		delete(s.subscriptions, uri)
+	}
+}
+
+func (s *session) setLogLevel(level LogLevel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logLevel = level
+	s.logLevelSet = true
+}
+
+// wantsLog reports whether the client receives a log message: its own at or above its level
+// (info until it sets one), those of other sessions only once it chose a level
+func (s *session) wantsLog(level LogLevel, own bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.logLevelSet {
+		return own && level >= LogInfo
+	}
+	return level >= s.logLevel
+}
+
+func (s *session) subscribedTo(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions[uri]
+}
+
+// subscribedToProject reports whether the session follows a resource named with the project at root
+func (s *session) subscribedToProject(root string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for uri := range s.subscriptions {
+		if _, project := splitResourceURI(uri); project == root {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *session) setRoots(roots []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roots = roots
+}
+
+func (s *session) rootPaths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.roots)
+}
+
+// protocolAtLeast reports whether the request's session speaks the given revision or a newer one.
+// Requests outside a session, or before initialize, are assumed to speak the newest revision.
+func protocolAtLeast(ctx context.Context, version string) bool {
+	sess := sessionFrom(ctx)
+	if sess == nil {
+		return true
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.protocolVersion == "" || sess.protocolVersion >= version
+}
+
+// isSupportedProtocol reports whether version is one of the revisions the server speaks
+func isSupportedProtocol(version string) bool {
+	return slices.Contains(supportedProtocolVersions, version)
+}
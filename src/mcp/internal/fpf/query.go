@@ -0,0 +1,431 @@
+package fpf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Holon query language, used by quint_query and `quint-code query`:
+//
+//	layer:L2 kind:system r<0.6 scope~payments updated>2026-01-01 depends:redis-cache cache sort:-r
+//
+// All terms must hold. A term is a field, an operator and a value:
+//
+//	field:value    equality (id, layer, kind, type), substring (title, content), direct
+//	               dependency (depends), decision context membership (context), and for
+//	               scope: the effective scope (G) admits the slice, e.g. scope:"env=prod"
+//	field~value    case-insensitive substring of a text field or of the declared scope
+//	r<0.6 f>=3     comparisons (<, <=, >, >=, =) on R_eff, effective F and the created
+//	               and updated dates (YYYY-MM-DD)
+//
+// Bare words and "quoted phrases" must appear in the title or content. A leading '-'
+// negates a term. sort:<field> orders the matches, sort:-<field> in descending order.
+
+type queryFieldKind int
+
+const (
+	queryTextField queryFieldKind = iota
+	queryNumberField
+	queryDateField
+	queryRelationField
+	queryScopeField
+)
+
+var queryFields = map[string]queryFieldKind{
+	"id":      queryTextField,
+	"layer":   queryTextField,
+	"kind":    queryTextField,
+	"type":    queryTextField,
+	"title":   queryTextField,
+	"content": queryTextField,
+	"scope":   queryScopeField,
+	"depends": queryRelationField,
+	"context": queryRelationField,
+	"r":       queryNumberField,
+	"f":       queryNumberField,
+	"created": queryDateField,
+	"updated": queryDateField,
+}
+
+var querySortKeys = []string{"id", "title", "layer", "kind", "r", "f", "created", "updated"}
+
+// queryTerm is one condition of a query. An empty field matches the title or content.
+type queryTerm struct {
+	field  string
+	op     string
+	value  string
+	negate bool
+	number float64
+	slice  assurance.ClaimScope
+}
+
+type holonQuery struct {
+	terms  []queryTerm
+	sortBy string
+	desc   bool
+}
+
+// queryHolon is a holon with what the query needs to know about its place in the graph
+type queryHolon struct {
+	db.Holon
+	report   *assurance.AssuranceReport
+	deps     []string // Direct dependencies (holons whose R flows into this one)
+	contexts []string // Decision contexts this holon is an alternative of
+}
+
+// parseQuery parses the query language described above
+func parseQuery(text string) (*holonQuery, error) {
+	tokens, err := splitQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	q := &holonQuery{sortBy: "id"}
+	for _, token := range tokens {
+		term, err := parseQueryTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		if term.field == "sort" {
+			q.sortBy, q.desc = term.value, term.negate
+			continue
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+// splitQuery splits a query on whitespace, keeping double-quoted phrases together
+func splitQuery(text string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuote := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("invalid query: unterminated quote")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func parseQueryTerm(token string) (queryTerm, error) {
+	var term queryTerm
+	if strings.HasPrefix(token, "-") && len(token) > 1 {
+		term.negate = true
+		token = token[1:]
+	}
+
+	i := strings.IndexAny(token, `:~<>="`)
+	if i <= 0 || token[i] == '"' {
+		term.value = strings.ToLower(strings.Trim(token, `"`))
+		if term.value == "" {
+			return term, fmt.Errorf("invalid query: empty phrase")
+		}
+		return term, nil
+	}
+
+	term.field = strings.ToLower(token[:i])
+	term.op = token[i : i+1]
+	if (term.op == "<" || term.op == ">") && i+1 < len(token) && token[i+1] == '=' {
+		term.op += "="
+	}
+	term.value = strings.Trim(token[i+len(term.op):], `"`)
+	if term.value == "" {
+		return term, fmt.Errorf("invalid query: %q has no value", token)
+	}
+
+	if term.field == "sort" {
+		if term.op != ":" {
+			return term, fmt.Errorf("invalid query: use sort:<field>")
+		}
+		if strings.HasPrefix(term.value, "-") {
+			term.negate, term.value = true, term.value[1:]
+		}
+		if !slices.Contains(querySortKeys, term.value) {
+			return term, fmt.Errorf("invalid query: cannot sort by %q (expected one of %s)", term.value, strings.Join(querySortKeys, ", "))
+		}
+		return term, nil
+	}
+
+	kind, ok := queryFields[term.field]
+	if !ok {
+		return term, fmt.Errorf("invalid query: unknown field %q", term.field)
+	}
+	switch kind {
+	case queryTextField:
+		if term.op != ":" && term.op != "~" {
+			return term, fmt.Errorf("invalid query: %s supports : and ~", term.field)
+		}
+	case queryRelationField:
+		if term.op != ":" {
+			return term, fmt.Errorf("invalid query: %s supports only :", term.field)
+		}
+	case queryScopeField:
+		switch term.op {
+		case ":":
+			slice, err := assurance.ParseScope(term.value)
+			if err != nil {
+				return term, fmt.Errorf("invalid query: scope slice: %v", err)
+			}
+			term.slice = slice
+		case "~":
+		default:
+			return term, fmt.Errorf("invalid query: scope supports : and ~")
+		}
+	case queryNumberField:
+		if term.op == "~" {
+			return term, fmt.Errorf("invalid query: %s supports :, =, <, <=, > and >=", term.field)
+		}
+		value := term.value
+		if term.field == "f" {
+			value = strings.TrimPrefix(strings.ToUpper(value), "F")
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return term, fmt.Errorf("invalid query: %s expects a number, got %q", term.field, term.value)
+		}
+		term.number = n
+	case queryDateField:
+		if term.op == "~" {
+			return term, fmt.Errorf("invalid query: %s supports :, =, <, <=, > and >=", term.field)
+		}
+		if _, err := time.Parse(time.DateOnly, term.value); err != nil {
+			return term, fmt.Errorf("invalid query: %s expects a date (YYYY-MM-DD), got %q", term.field, term.value)
+		}
+	}
+	return term, nil
+}
+
+func (q *holonQuery) matches(h *queryHolon) bool {
+	for _, term := range q.terms {
+		if term.matches(h) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (term queryTerm) matches(h *queryHolon) bool {
+	switch term.field {
+	case "":
+		return containsFold(h.Title, term.value) || containsFold(h.Content, term.value)
+	case "id", "layer", "kind", "type":
+		value := h.text(term.field)
+		if term.op == "~" {
+			return containsFold(value, term.value)
+		}
+		return strings.EqualFold(value, term.value)
+	case "title":
+		return containsFold(h.Title, term.value)
+	case "content":
+		return containsFold(h.Content, term.value)
+	case "scope":
+		if term.op == "~" {
+			return containsFold(h.Scope.String, term.value)
+		}
+		return !h.report.Scope.IsEmpty() && h.report.Scope.Check(term.slice).Applies
+	case "depends":
+		return slices.Contains(h.deps, term.value)
+	case "context":
+		return slices.Contains(h.contexts, term.value)
+	case "r":
+		return compareQuery(h.report.FinalScore, term.op, term.number)
+	case "f":
+		return compareQuery(float64(h.report.Formality), term.op, term.number)
+	case "created", "updated":
+		date := queryDate(h, term.field)
+		if date == "" {
+			return false
+		}
+		return compareQuery(float64(strings.Compare(date, term.value)), term.op, 0)
+	}
+	return false
+}
+
+// text returns a text field of the holon for matching and sorting
+func (h *queryHolon) text(field string) string {
+	switch field {
+	case "id":
+		return h.ID
+	case "layer":
+		return h.Layer
+	case "kind":
+		return h.Kind.String
+	case "type":
+		return h.Type
+	case "title":
+		return strings.ToLower(h.Title)
+	case "created", "updated":
+		return queryDate(h, field)
+	}
+	return ""
+}
+
+func compareQuery(value float64, op string, target float64) bool {
+	switch op {
+	case "<":
+		return value < target
+	case "<=":
+		return value <= target
+	case ">":
+		return value > target
+	case ">=":
+		return value >= target
+	default:
+		return value == target
+	}
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// queryDate returns the created or updated day of a holon; updated falls back to created
+func queryDate(h *queryHolon, field string) string {
+	t := h.CreatedAt
+	if field == "updated" && h.UpdatedAt.Valid {
+		t = h.UpdatedAt
+	}
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.DateOnly)
+}
+
+func (q *holonQuery) sort(holons []*queryHolon) {
+	less := func(a, b *queryHolon) bool {
+		switch q.sortBy {
+		case "r":
+			return a.report.FinalScore < b.report.FinalScore
+		case "f":
+			return a.report.Formality < b.report.Formality
+		}
+		return a.text(q.sortBy) < b.text(q.sortBy)
+	}
+	sort.SliceStable(holons, func(i, j int) bool {
+		if q.desc {
+			return less(holons[j], holons[i])
+		}
+		return less(holons[i], holons[j])
+	})
+}
+
+// Query returns the holons matching a query in the holon query language, at most limit of them
+func (t *Tools) Query(ctx context.Context, query string, limit int) (*QueryResult, error) {
+	defer t.RecordWork(ctx, "Query", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := t.DB.ListHolons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	holons := make([]*queryHolon, len(rows))
+	for i, row := range rows {
+		holons[i] = &queryHolon{Holon: row}
+	}
+
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := calc.NewBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadQueryGraph(ctx, batch, holons); err != nil {
+		return nil, err
+	}
+
+	var matches []*queryHolon
+	for _, h := range holons {
+		if q.matches(h) {
+			matches = append(matches, h)
+		}
+	}
+	q.sort(matches)
+
+	result := &QueryResult{Query: query, Total: len(matches), Holons: []QueryMatch{}}
+	for _, h := range matches {
+		if limit > 0 && len(result.Holons) == limit {
+			break
+		}
+		result.Holons = append(result.Holons, QueryMatch{
+			ID:        h.ID,
+			Title:     h.Title,
+			Layer:     h.Layer,
+			Kind:      h.Kind.String,
+			R:         h.report.FinalScore,
+			Formality: h.report.Formality,
+			Scope:     h.report.Scope.String(),
+			Updated:   queryDate(h, "updated"),
+		})
+	}
+	return result, nil
+}
+
+// loadQueryGraph fills in the assurance report, dependencies and decision contexts of holons
+func loadQueryGraph(ctx context.Context, batch *assurance.Batch, holons []*queryHolon) error {
+	graph := batch.Graph()
+
+	contexts := make(map[string][]string)
+	for _, id := range graph.HolonIDs() {
+		members, err := graph.Members(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			contexts[member] = append(contexts[member], id)
+		}
+	}
+
+	for i, h := range holons {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report, err := batch.Report(ctx, h.ID)
+		if err != nil {
+			return err
+		}
+		edges, err := graph.Dependencies(ctx, h.ID)
+		if err != nil {
+			return err
+		}
+		h.report = report
+		for _, e := range edges {
+			h.deps = append(h.deps, e.ID)
+		}
+		h.contexts = contexts[h.ID]
+		reportProgress(ctx, float64(i+1), float64(len(holons)), "")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package fpf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := parseQuery(`layer:L2 -kind:episteme r<=0.6 f>F2 scope:"env=prod" "read through" sort:-updated`)
+	if err != nil {
+		t.Fatalf("parseQuery failed: %v", err)
+	}
+	if q.sortBy != "updated" || !q.desc {
+		t.Errorf("Expected descending sort by updated, got %s (desc %v)", q.sortBy, q.desc)
+	}
+	want := []queryTerm{
+		{field: "layer", op: ":", value: "L2"},
+		{field: "kind", op: ":", value: "episteme", negate: true},
+		{field: "r", op: "<=", value: "0.6", number: 0.6},
+		{field: "f", op: ">", value: "F2", number: 2},
+		{field: "scope", op: ":", value: "env=prod"},
+		{field: "", value: "read through"},
+	}
+	if len(q.terms) != len(want) {
+		t.Fatalf("Expected %d terms, got %+v", len(want), q.terms)
+	}
+	for i, w := range want {
+		got := q.terms[i]
+		if got.field != w.field || got.op != w.op || got.value != w.value || got.negate != w.negate || got.number != w.number {
+			t.Errorf("Term %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+
+	for query, wantErr := range map[string]string{
+		`owner:bob`:           "unknown field",
+		`r~0.5`:               "supports",
+		`r>high`:              "expects a number",
+		`updated>yesterday`:   "expects a date",
+		`depends<x`:           "supports only :",
+		`sort:weight`:         "cannot sort by",
+		`title:"unterminated`: "unterminated quote",
+		`layer:`:              "has no value",
+	} {
+		if _, err := parseQuery(query); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("parseQuery(%q): expected error containing %q, got %v", query, wantErr, err)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	ctx := t.Context()
+
+	holons := []struct{ id, kind, layer, title, content, scope string }{
+		{"redis-cache", "system", "L2", "Redis cache", "Read-through cache in Redis", "service=payments; env=prod"},
+		{"cdn-edge", "system", "L2", "CDN edge", "Cache static assets at the edge", "env=prod"},
+		{"review-process", "episteme", "L1", "Review process", "Two reviewers per change", "global"},
+		{"caching-decision", "system", "L0", "Caching decision", "Which cache to use", "global"},
+	}
+	for _, h := range holons {
+		if err := tools.DB.CreateHolon(ctx, h.id, "hypothesis", h.kind, h.layer, h.title, h.content, "default", h.scope, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tools.DB.CreateRelation(ctx, "redis-cache", "componentOf", "cdn-edge", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.CreateRelation(ctx, "redis-cache", "memberOf", "caching-decision", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-redis", "redis-cache", "test", "ok", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-review", "review-process", "test", "ok", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(query string) []string {
+		t.Helper()
+		result, err := tools.Query(ctx, query, 0)
+		if err != nil {
+			t.Fatalf("Query(%q) failed: %v", query, err)
+		}
+		var ids []string
+		for _, h := range result.Holons {
+			ids = append(ids, h.ID)
+		}
+		return ids
+	}
+
+	for query, want := range map[string]string{
+		"layer:L2 kind:system":          "cdn-edge redis-cache",
+		"-kind:system":                  "review-process",
+		"cache -layer:L0":               "cdn-edge redis-cache",
+		`"read-through"`:                "redis-cache",
+		"scope~payments":                "redis-cache",
+		"scope:env=prod layer:L2":       "cdn-edge redis-cache",
+		"scope:service=checkout":        "review-process",
+		"depends:redis-cache":           "cdn-edge",
+		"context:caching-decision":      "redis-cache",
+		"r>=0.5 sort:-id":               "review-process redis-cache",
+		"r<0.5":                         "caching-decision cdn-edge",
+		"updated>2000-01-01 title~edge": "cdn-edge",
+		"created<2000-01-01":            "",
+	} {
+		if got := strings.Join(ids(query), " "); got != want {
+			t.Errorf("Query(%q) = %q, want %q", query, got, want)
+		}
+	}
+
+	result := callStructured(t, server, "quint_query", `{"query":"kind:system sort:-r","limit":1}`)
+	matches, _ := result["holons"].([]interface{})
+	if result["total"] != 3.0 || len(matches) != 1 || matches[0].(map[string]interface{})["id"] != "redis-cache" {
+		t.Errorf("Expected redis-cache first of 3 system holons, got %+v", result)
+	}
+}
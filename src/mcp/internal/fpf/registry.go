@@ -0,0 +1,396 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// ToolResult is the structured output of a tool. It is served as structuredContent and
+// rendered by Markdown for the text content; the CLI prints either.
+type ToolResult interface {
+	Markdown() string
+}
+
+// toolDef is a registered MCP tool. Its input struct generates the JSON Schema served by
+// tools/list; tools/call validates arguments against that schema before decoding them.
+// The output struct generates the outputSchema.
+type toolDef struct {
+	name        string
+	description string
+	hints       toolHints
+	input       *Schema
+	output      *Schema
+	call        func(ctx context.Context, t *Tools, args map[string]interface{}) (ToolResult, error)
+}
+
+// toolHints classifies what a tool does to the knowledge base. They are served as MCP tool
+// annotations and decide which calls a read-only server refuses.
+type toolHints struct {
+	readOnly    bool     // Never changes the knowledge base
+	destructive bool     // May overwrite or downgrade existing knowledge, not only add to it
+	idempotent  bool     // Repeating a call with the same arguments has no further effect
+	mutatingBy  []string // Arguments that make an otherwise read-only call change the knowledge base
+}
+
+// Tool classes used by the registry
+var (
+	readOnlyTool    = toolHints{readOnly: true, idempotent: true}
+	additiveTool    = toolHints{}
+	idempotentTool  = toolHints{idempotent: true}
+	overwritingTool = toolHints{destructive: true, idempotent: true}
+)
+
+// ToolAnnotations are the MCP behaviour hints of a tool
+type ToolAnnotations struct {
+	ReadOnlyHint    bool `json:"readOnlyHint"`
+	DestructiveHint bool `json:"destructiveHint"`
+	IdempotentHint  bool `json:"idempotentHint"`
+	OpenWorldHint   bool `json:"openWorldHint"`
+}
+
+// annotations reports the tool's hints. Tools that only mutate with some arguments are not
+// read-only; every tool works on the local knowledge base only (closed world).
+func (d toolDef) annotations() *ToolAnnotations {
+	return &ToolAnnotations{
+		ReadOnlyHint:    d.hints.readOnly && len(d.hints.mutatingBy) == 0,
+		DestructiveHint: d.hints.destructive,
+		IdempotentHint:  d.hints.idempotent,
+	}
+}
+
+// mutates reports whether a call with these arguments changes the knowledge base
+func (d toolDef) mutates(args map[string]interface{}) bool {
+	if !d.hints.readOnly {
+		return true
+	}
+	for _, name := range d.hints.mutatingBy {
+		if v, ok := args[name]; ok && v != nil && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// projectArgument is accepted by every tool: it selects the project the call works on
+var projectArgument = &Schema{
+	Type:        "string",
+	Description: "Project root path or directory name, for servers with several projects. Defaults to the client's only root or the server's project.",
+}
+
+// newTool registers a tool whose arguments decode into In and whose result is Out
+func newTool[In any, Out ToolResult](name, description string, hints toolHints, run func(ctx context.Context, t *Tools, in In) (Out, error)) toolDef {
+	schema := schemaFor(reflect.TypeOf((*In)(nil)).Elem())
+	schema.Properties["project"] = projectArgument
+	return toolDef{
+		name:        name,
+		description: description,
+		hints:       hints,
+		input:       schema,
+		output:      schemaFor(reflect.TypeOf((*Out)(nil)).Elem()),
+		call: func(ctx context.Context, t *Tools, args map[string]interface{}) (ToolResult, error) {
+			var in In
+			if err := schema.decodeArgs(args, &in); err != nil {
+				return nil, err
+			}
+			out, err := run(ctx, t, in)
+			if err != nil {
+				return nil, err
+			}
+			return out, nil
+		},
+	}
+}
+
+type noInput struct{}
+
+type recordContextInput struct {
+	Vocabulary string `json:"vocabulary" schema:"required" description:"Key terms"`
+	Invariants string `json:"invariants" schema:"required" description:"System rules"`
+}
+
+type proposeInput struct {
+	Title           string   `json:"title" schema:"required" description:"Title"`
+	Content         string   `json:"content" schema:"required" description:"Description"`
+	Scope           string   `json:"scope" schema:"required" description:"Scope (G) - where this hypothesis applies. Structured form: 'service=payments,checkout; env=prod' (dimensions split by ';', values by ','), or 'global'. G is intersected along depends_on and united across decision_context alternatives."`
+	Kind            string   `json:"kind" schema:"required,enum=system|episteme" description:"system=code/architecture, episteme=process/methodology"`
+	Rationale       string   `json:"rationale" schema:"required" description:"JSON: {anomaly, approach, alternatives_rejected}"`
+	DecisionContext string   `json:"decision_context" description:"Parent decision ID to GROUP competing alternatives. Does NOT affect R_eff. Use when multiple hypotheses solve the same problem. Example: 'caching-decision' groups 'redis-caching' and 'cdn-edge'. Creates MemberOf relation."`
+	DependsOn       []string `json:"depends_on" description:"IDs of holons this hypothesis REQUIRES to work. CRITICAL: Affects R_eff via WLNK - if dependency has low R, this inherits that ceiling. Use when: (1) builds on another hypothesis, (2) needs another to function, (3) dependency failure invalidates this. Leave empty for independent hypotheses. Creates ComponentOf/ConstituentOf."`
+	DependencyCL    int      `json:"dependency_cl" schema:"minimum=1,maximum=3,default=3" description:"Congruence level for dependencies. CL3=same context (no penalty), CL2=similar (10% penalty), CL1=different (30% penalty)."`
+	Formality       int      `json:"formality" schema:"minimum=0,maximum=9,default=0" description:"Formality level F0-F9 of the claim (F0=informal prose ... F9=machine-checked proof). Effective F is min(F) over dependencies (WLNK)."`
+}
+
+type verifyInput struct {
+	HypothesisID string `json:"hypothesis_id" schema:"required"`
+	ChecksJSON   string `json:"checks_json" schema:"required" description:"JSON of checks"`
+	Verdict      string `json:"verdict" schema:"required,enum=PASS|FAIL|REFINE"`
+	Formality    *int   `json:"formality" schema:"minimum=0,maximum=9" description:"Formality level F0-F9 reached by the verification (optional, applied on PASS). Omit to keep the current level."`
+}
+
+type testInput struct {
+	HypothesisID string `json:"hypothesis_id" schema:"required"`
+	TestType     string `json:"test_type" schema:"required" description:"internal or research"`
+	Result       string `json:"result" schema:"required" description:"Test output/findings"`
+	Verdict      string `json:"verdict" schema:"required,enum=PASS|FAIL|REFINE"`
+}
+
+type auditInput struct {
+	HypothesisID string `json:"hypothesis_id" schema:"required"`
+	Risks        string `json:"risks" schema:"required" description:"Risk analysis"`
+}
+
+type decideInput struct {
+	Title           string   `json:"title" schema:"required"`
+	WinnerID        string   `json:"winner_id" schema:"required"`
+	RejectedIDs     []string `json:"rejected_ids" description:"IDs of rejected L2 alternatives"`
+	Context         string   `json:"context" schema:"required"`
+	Decision        string   `json:"decision" schema:"required"`
+	Rationale       string   `json:"rationale" schema:"required"`
+	Consequences    string   `json:"consequences" schema:"required"`
+	Characteristics string   `json:"characteristics"`
+}
+
+type holonInput struct {
+	HolonID string `json:"holon_id" schema:"required" description:"ID of the holon"`
+}
+
+// whatIfChangeInput mirrors assurance.Change on the wire
+type whatIfChangeInput struct {
+	Op           string `json:"op" schema:"required,enum=add_evidence|set_cl|expire_evidence"`
+	HolonID      string `json:"holon_id,omitempty" description:"add_evidence: holon receiving the evidence; set_cl: dependent holon"`
+	DependencyID string `json:"dependency_id,omitempty" description:"set_cl: dependency at the other end of the edge"`
+	CL           int    `json:"cl,omitempty" schema:"minimum=0,maximum=3" description:"set_cl: new congruence level"`
+	EvidenceID   string `json:"evidence_id,omitempty" description:"expire_evidence: evidence to expire and decay fully to its floor"`
+	Verdict      string `json:"verdict,omitempty" description:"add_evidence: pass (default), degrade or fail"`
+	Type         string `json:"type,omitempty" description:"add_evidence: evidence type (default internal)"`
+	Level        string `json:"level,omitempty" description:"add_evidence: assurance level (default L2)"`
+}
+
+type whatIfInput struct {
+	HolonID string              `json:"holon_id" schema:"required" description:"ID of the holon to evaluate"`
+	Changes []whatIfChangeInput `json:"changes" description:"Hypothetical changes, applied in order"`
+}
+
+type checkScopeInput struct {
+	HolonID string `json:"holon_id" schema:"required" description:"ID of the holon or DRR"`
+	Slice   string `json:"slice" schema:"required" description:"Context slice, e.g. 'service=payments; env=prod'"`
+}
+
+type checkDecayInput struct {
+	Deprecate      string `json:"deprecate" description:"Hypothesis ID to deprecate (L2→L1 or L1→L0)"`
+	WaiveID        string `json:"waive_id" description:"Evidence ID to waive"`
+	WaiveUntil     string `json:"waive_until" description:"ISO date until which waiver is valid (required with waive_id)"`
+	WaiveRationale string `json:"waive_rationale" description:"Reason for accepting stale evidence (required with waive_id)"`
+}
+
+type queryInput struct {
+	Query string `json:"query" schema:"required" description:"Holon query, e.g. 'layer:L2 kind:system r<0.6 scope~payments updated>2026-01-01 depends:redis-cache caching sort:-r'. Terms are ANDed; '-' negates a term. Fields: id, layer, kind, type, title, content (: exact or contains, ~ contains), scope (~ contains the text, : effective scope admits a slice like 'env=prod'), depends (direct dependency), context (decision context), r, f, created, updated (<, <=, >, >=, =). Bare words and \"quoted phrases\" search title and content. sort:<field> or sort:-<field> (id, title, layer, kind, r, f, created, updated)."`
+	Limit int    `json:"limit" schema:"minimum=1,default=50" description:"Maximum number of holons returned"`
+}
+
+type searchInput struct {
+	Query string `json:"query" schema:"required" description:"Words to find in holons, DRRs and evidence (all must match). \"Quoted phrases\" match exactly, cach* matches prefixes; words are stemmed, so caching also finds cache."`
+	Limit int    `json:"limit" schema:"minimum=1,default=20" description:"Maximum number of hits"`
+}
+
+// toolRegistry lists the MCP tools in the order tools/list serves them
+var toolRegistry = []toolDef{
+	newTool("quint_status", "Get current FPF phase and context.", readOnlyTool,
+		func(ctx context.Context, t *Tools, _ noInput) (*PhaseResult, error) {
+			return &PhaseResult{Phase: string(t.FSM.State.Phase)}, nil
+		}),
+	newTool("quint_init", "Initialize FPF project structure.", idempotentTool,
+		func(ctx context.Context, t *Tools, _ noInput) (*PhaseResult, error) {
+			if err := t.InitProject(); err != nil {
+				return nil, err
+			}
+			t.enterPhase(ctx, PhaseAbduction)
+			return &PhaseResult{Phase: string(PhaseAbduction), message: "Initialized. Phase: ABDUCTION"}, nil
+		}),
+	newTool("quint_record_context", "Record the Bounded Context (A.1.1).", overwritingTool,
+		func(ctx context.Context, t *Tools, in recordContextInput) (*ChangeResult, error) {
+			path, err := t.RecordContext(in.Vocabulary, in.Invariants)
+			if err != nil {
+				return nil, err
+			}
+			return &ChangeResult{Path: path}, nil
+		}),
+	newTool("quint_propose", "Propose a new hypothesis (L0). IMPORTANT: Consider depends_on for dependencies and decision_context for grouping alternatives.", additiveTool,
+		func(ctx context.Context, t *Tools, in proposeInput) (*ChangeResult, error) {
+			t.enterPhase(ctx, PhaseAbduction)
+			path, err := t.ProposeHypothesis(ctx, in.Title, in.Content, in.Scope, in.Kind, in.Rationale, in.DecisionContext, in.DependsOn, in.DependencyCL, in.Formality)
+			if err != nil {
+				return nil, err
+			}
+			return t.changeResult(ctx, strings.TrimSuffix(filepath.Base(path), ".md"), path, ""), nil
+		}),
+	newTool("quint_verify", "Record verification results (L0 -> L1).", additiveTool,
+		func(ctx context.Context, t *Tools, in verifyInput) (*ChangeResult, error) {
+			t.enterPhase(ctx, PhaseDeduction)
+			formality := -1
+			if in.Formality != nil {
+				formality = *in.Formality
+			}
+			message, err := t.VerifyHypothesis(ctx, in.HypothesisID, in.ChecksJSON, in.Verdict, formality)
+			if err != nil {
+				return nil, err
+			}
+			return t.changeResult(ctx, in.HypothesisID, "", message), nil
+		}),
+	newTool("quint_test", "Record validation results (L1 -> L2).", additiveTool,
+		func(ctx context.Context, t *Tools, in testInput) (*ChangeResult, error) {
+			t.enterPhase(ctx, PhaseInduction)
+			assLevel := "L2"
+			if in.Verdict != "PASS" {
+				assLevel = "L1"
+			}
+			message, err := t.ManageEvidence(ctx, PhaseInduction, "add", in.HypothesisID, in.TestType, in.Result, in.Verdict, assLevel, "test-runner", "")
+			if err != nil {
+				return nil, err
+			}
+			return t.changeResult(ctx, in.HypothesisID, "", message), nil
+		}),
+	newTool("quint_audit", "Record audit/trust score (R_eff).", additiveTool,
+		func(ctx context.Context, t *Tools, in auditInput) (*ChangeResult, error) {
+			message, err := t.AuditEvidence(ctx, in.HypothesisID, in.Risks)
+			if err != nil {
+				return nil, err
+			}
+			return t.changeResult(ctx, in.HypothesisID, "", message), nil
+		}),
+	newTool("quint_decide", "Finalize decision (DRR).", additiveTool,
+		func(ctx context.Context, t *Tools, in decideInput) (*DecisionResult, error) {
+			t.FSM.State.Phase = PhaseDecision
+			path, err := t.FinalizeDecision(ctx, in.Title, in.WinnerID, in.RejectedIDs, in.Context, in.Decision, in.Rationale, in.Consequences, in.Characteristics)
+			if err != nil {
+				return nil, err
+			}
+			t.enterPhase(ctx, PhaseIdle)
+			return &DecisionResult{DRRID: t.Slugify(in.Title), Path: path, WinnerID: in.WinnerID, RejectedIDs: in.RejectedIDs}, nil
+		}),
+	newTool("quint_actualize", "Reconcile the project's FPF state with recent repository changes.", idempotentTool,
+		func(ctx context.Context, t *Tools, _ noInput) (*ActualizeResult, error) {
+			report, err := t.Actualize(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return &ActualizeResult{LastCommit: t.FSM.State.LastCommit, Report: report}, nil
+		}),
+	newTool("quint_audit_tree", "Visualize the assurance tree for a holon, showing R scores, F levels, dependencies, and CL penalties.", readOnlyTool,
+		func(ctx context.Context, t *Tools, in holonInput) (*AuditTreeResult, error) {
+			return t.AuditTree(ctx, in.HolonID)
+		}),
+	newTool("quint_calculate_r", "Calculate the effective reliability (R_eff) and formality (F) for a holon with detailed breakdown.", readOnlyTool,
+		func(ctx context.Context, t *Tools, in holonInput) (*ReliabilityResult, error) {
+			return t.Reliability(ctx, in.HolonID)
+		}),
+	newTool("quint_what_if", "Simulate hypothetical changes and show how R_eff and the weakest link would move, without persisting anything. Without changes: ranks the single changes (new PASS, higher CL, expired evidence) that move R_eff most.", readOnlyTool,
+		func(ctx context.Context, t *Tools, in whatIfInput) (*WhatIfResult, error) {
+			changesJSON := ""
+			if len(in.Changes) > 0 {
+				data, err := json.Marshal(in.Changes)
+				if err != nil {
+					return nil, err
+				}
+				changesJSON = string(data)
+			}
+			return t.Simulate(ctx, in.HolonID, changesJSON)
+		}),
+	newTool("quint_check_scope", "Check whether a holon or decision applies to a given context slice, using its effective scope (G).", readOnlyTool,
+		func(ctx context.Context, t *Tools, in checkScopeInput) (*ScopeResult, error) {
+			return t.ScopeCheck(ctx, in.HolonID, in.Slice)
+		}),
+	newTool("quint_check_decay", "Check evidence freshness and manage stale decisions. Without parameters: shows freshness report, including holons whose R_eff will decay below the assurance threshold soon. With deprecate: downgrades hypothesis. With waive: records temporary risk acceptance.", toolHints{readOnly: true, destructive: true, mutatingBy: []string{"deprecate", "waive_id"}},
+		func(ctx context.Context, t *Tools, in checkDecayInput) (*DecayResult, error) {
+			return t.Decay(ctx, in.Deprecate, in.WaiveID, in.WaiveUntil, in.WaiveRationale)
+		}),
+	newTool("quint_query", "Find holons with a small query language: filter by layer, kind, R_eff, F, scope, dates, dependencies and decision context, search title and content, and sort.", readOnlyTool,
+		func(ctx context.Context, t *Tools, in queryInput) (*QueryResult, error) {
+			return t.Query(ctx, in.Query, in.Limit)
+		}),
+	newTool("quint_search", "Full-text search over holons, DRRs and evidence. Returns ranked snippets linked to holon IDs. Use quint_query to filter by layer, R_eff, scope or dates.", readOnlyTool,
+		func(ctx context.Context, t *Tools, in searchInput) (*SearchResult, error) {
+			return t.Search(ctx, in.Query, in.Limit)
+		}),
+	newTool("quint_check_graph", "Check knowledge graph integrity: dependency cycles (strongly connected components), relations pointing at missing holons, and evidence attached to missing holons.", readOnlyTool,
+		func(ctx context.Context, t *Tools, _ noInput) (*GraphCheckResult, error) {
+			return t.GraphIntegrity(ctx)
+		}),
+}
+
+// ErrUnknownTool is returned for a tool name that is not registered
+type ErrUnknownTool struct {
+	Name string
+}
+
+func (e *ErrUnknownTool) Error() string {
+	return "Unknown tool: " + e.Name
+}
+
+// CallTool runs a registered tool the way tools/call does: it validates the arguments against the
+// tool's input schema, checks the phase preconditions and calls the tool. Arguments are JSON values
+// (numbers as float64). Validation failures are *ErrInvalidArguments, failed preconditions
+// *PreconditionError; both leave the knowledge base untouched. A call that cannot change the
+// knowledge base writes nothing at all; any other call re-caches R scores once it succeeds.
+func (t *Tools) CallTool(ctx context.Context, name string, args map[string]interface{}) (ToolResult, error) {
+	tool, ok := lookupTool(name)
+	if !ok {
+		return nil, &ErrUnknownTool{Name: name}
+	}
+	if fields := tool.input.Validate(args); len(fields) > 0 {
+		return nil, &ErrInvalidArguments{Fields: fields}
+	}
+	mutates := tool.mutates(args)
+	if !mutates {
+		ctx = withoutBookkeeping(ctx)
+	}
+
+	flat := make(map[string]string)
+	for k, v := range args {
+		if s, ok := v.(string); ok {
+			flat[k] = s
+		}
+	}
+	if err := t.CheckPreconditions(ctx, tool.name, flat); err != nil {
+		t.AuditLog(ctx, tool.name, "precondition_failed", "agent", "", "BLOCKED", flat, err.Error())
+		return nil, err
+	}
+
+	result, err := tool.call(ctx, t, args)
+	if err == nil && mutates {
+		t.refreshScores(ctx)
+	}
+	return result, err
+}
+
+// lookupTool finds a registered tool by name
+func lookupTool(name string) (toolDef, bool) {
+	for _, tool := range toolRegistry {
+		if tool.name == name {
+			return tool, true
+		}
+	}
+	return toolDef{}, false
+}
+
+// enterPhase records the phase a tool call moves the FSM into
+func (t *Tools) enterPhase(ctx context.Context, phase Phase) {
+	t.FSM.State.Phase = phase
+	if err := t.FSM.SaveState("default"); err != nil {
+		t.Log.Warnf(ctx, "failed to save state: %v", err)
+	}
+}
+
+// changeResult describes a holon after a tool changed it, with its current layer
+func (t *Tools) changeResult(ctx context.Context, holonID, path, message string) *ChangeResult {
+	result := &ChangeResult{HolonID: holonID, Path: path, Message: message}
+	if t.DB != nil {
+		if holon, err := t.DB.GetHolon(ctx, holonID); err == nil {
+			result.Layer = holon.Layer
+		}
+	}
+	return result
+}
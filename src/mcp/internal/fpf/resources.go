@@ -0,0 +1,273 @@
+package fpf
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// Resource URIs exposed over MCP
+const (
+	resourceScheme    = "quint://"
+	contextURI        = resourceScheme + "context"
+	holonURIPrefix    = resourceScheme + "holon/"
+	drrURIPrefix      = resourceScheme + "drr/"
+	evidenceURIPrefix = resourceScheme + "evidence/"
+)
+
+// Resources of a project other than the one a session reads by default carry its root in a
+// project query, e.g. quint://holon/redis-cache?project=%2Fsrc%2Fpayments
+const resourceProjectParam = "project"
+
+// Layers listed by resources/list, in workflow order
+var resourceLayers = []string{"L0", "L1", "L2", "invalid"}
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ErrResourceNotFound is returned by ReadResource for unknown URIs
+type ErrResourceNotFound struct {
+	URI string
+}
+
+func (e *ErrResourceNotFound) Error() string {
+	return fmt.Sprintf("resource not found: %s", e.URI)
+}
+
+// ListResources returns the bounded context, every holon and every DRR
+func (t *Tools) ListResources(ctx context.Context) ([]Resource, error) {
+	var resources []Resource
+
+	if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "context.md")); err == nil {
+		resources = append(resources, Resource{
+			URI:         contextURI,
+			Name:        "Bounded Context",
+			Description: "Vocabulary and invariants recorded by /q0-init",
+			MimeType:    "text/markdown",
+		})
+	}
+
+	if t.DB == nil {
+		return resources, nil
+	}
+
+	for _, layer := range resourceLayers {
+		holons, err := t.DB.ListHolonsByLayer(ctx, layer)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holons {
+			resources = append(resources, Resource{
+				URI:         holonURIPrefix + h.ID,
+				Name:        h.Title,
+				Description: fmt.Sprintf("%s %s", layer, h.Type),
+				MimeType:    "text/markdown",
+			})
+		}
+	}
+
+	drrs, err := t.DB.ListHolonsByLayer(ctx, "DRR")
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range drrs {
+		resources = append(resources, Resource{
+			URI:         drrURIPrefix + h.ID,
+			Name:        h.Title,
+			Description: "Design Rationale Record",
+			MimeType:    "text/markdown",
+		})
+	}
+
+	return resources, nil
+}
+
+// ListResourceTemplates returns the parameterized resource URIs
+func (t *Tools) ListResourceTemplates() []ResourceTemplate {
+	return []ResourceTemplate{
+		{URITemplate: holonURIPrefix + "{id}", Name: "Holon", Description: "Hypothesis with its layer, scope and content", MimeType: "text/markdown"},
+		{URITemplate: drrURIPrefix + "{id}", Name: "Design Rationale Record", Description: "Finalized decision", MimeType: "text/markdown"},
+		{URITemplate: evidenceURIPrefix + "{holon_id}", Name: "Evidence", Description: "Evidence attached to a holon", MimeType: "application/json"},
+	}
+}
+
+// ReadResource returns the contents of a quint:// resource. Holons and DRRs are served
+// from their projection files when present (validated against the content hash) and
+// rendered from the database otherwise.
+func (t *Tools) ReadResource(ctx context.Context, uri string) (ResourceContents, error) {
+	switch {
+	case uri == contextURI:
+		data, err := os.ReadFile(filepath.Join(t.GetFPFDir(), "context.md"))
+		if os.IsNotExist(err) {
+			return ResourceContents{}, &ErrResourceNotFound{URI: uri}
+		}
+		if err != nil {
+			return ResourceContents{}, err
+		}
+		return ResourceContents{URI: uri, MimeType: "text/markdown", Text: string(data)}, nil
+
+	case strings.HasPrefix(uri, holonURIPrefix):
+		text, err := t.readHolonResource(ctx, uri, strings.TrimPrefix(uri, holonURIPrefix), false)
+		if err != nil {
+			return ResourceContents{}, err
+		}
+		return ResourceContents{URI: uri, MimeType: "text/markdown", Text: text}, nil
+
+	case strings.HasPrefix(uri, drrURIPrefix):
+		text, err := t.readHolonResource(ctx, uri, strings.TrimPrefix(uri, drrURIPrefix), true)
+		if err != nil {
+			return ResourceContents{}, err
+		}
+		return ResourceContents{URI: uri, MimeType: "text/markdown", Text: text}, nil
+
+	case strings.HasPrefix(uri, evidenceURIPrefix):
+		text, err := t.readEvidenceResource(ctx, uri, strings.TrimPrefix(uri, evidenceURIPrefix))
+		if err != nil {
+			return ResourceContents{}, err
+		}
+		return ResourceContents{URI: uri, MimeType: "application/json", Text: text}, nil
+	}
+
+	return ResourceContents{}, &ErrResourceNotFound{URI: uri}
+}
+
+// lookupHolon fetches a holon for a resource URI, reporting unknown IDs as ErrResourceNotFound
+func (t *Tools) lookupHolon(ctx context.Context, uri, id string) (db.Holon, error) {
+	if t.DB == nil {
+		return db.Holon{}, fmt.Errorf("DB not initialized")
+	}
+	holon, err := t.DB.GetHolon(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Holon{}, &ErrResourceNotFound{URI: uri}
+	}
+	return holon, err
+}
+
+func (t *Tools) readHolonResource(ctx context.Context, uri, id string, drr bool) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return "", &ErrResourceNotFound{URI: uri}
+	}
+	holon, err := t.lookupHolon(ctx, uri, id)
+	if err != nil {
+		return "", err
+	}
+	if drr != (holon.Layer == "DRR") {
+		return "", &ErrResourceNotFound{URI: uri}
+	}
+
+	var path string
+	if drr {
+		matches, _ := filepath.Glob(filepath.Join(t.GetFPFDir(), "decisions", "DRR-*-"+id+".md"))
+		if len(matches) > 0 {
+			path = matches[len(matches)-1]
+		}
+	} else {
+		path = filepath.Join(t.GetFPFDir(), "knowledge", holon.Layer, id+".md")
+	}
+	if path != "" {
+		if content, _, err := t.ReadWithValidation(ctx, path); err == nil {
+			return content, nil
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# %s\n\n", holon.Title))
+	result.WriteString(fmt.Sprintf("- ID: %s\n", holon.ID))
+	result.WriteString(fmt.Sprintf("- Layer: %s\n", holon.Layer))
+	if holon.Kind.Valid && holon.Kind.String != "" {
+		result.WriteString(fmt.Sprintf("- Kind: %s\n", holon.Kind.String))
+	}
+	if holon.Scope.Valid && holon.Scope.String != "" {
+		result.WriteString(fmt.Sprintf("- Scope: %s\n", holon.Scope.String))
+	}
+	if holon.CachedRScore.Valid {
+		result.WriteString(fmt.Sprintf("- R (cached): %.2f\n", holon.CachedRScore.Float64))
+	}
+	result.WriteString("\n")
+	result.WriteString(holon.Content)
+	return result.String(), nil
+}
+
+func (t *Tools) readEvidenceResource(ctx context.Context, uri, holonID string) (string, error) {
+	if _, err := t.lookupHolon(ctx, uri, holonID); err != nil {
+		return "", err
+	}
+	evidence, err := t.DB.GetEvidence(ctx, holonID)
+	if err != nil {
+		return "", err
+	}
+
+	type evidenceItem struct {
+		ID             string `json:"id"`
+		Type           string `json:"type"`
+		Verdict        string `json:"verdict"`
+		AssuranceLevel string `json:"assurance_level,omitempty"`
+		CarrierRef     string `json:"carrier_ref,omitempty"`
+		ValidUntil     string `json:"valid_until,omitempty"`
+		Content        string `json:"content"`
+	}
+	items := make([]evidenceItem, 0, len(evidence))
+	for _, e := range evidence {
+		item := evidenceItem{
+			ID:             e.ID,
+			Type:           e.Type,
+			Verdict:        e.Verdict,
+			AssuranceLevel: e.AssuranceLevel.String,
+			CarrierRef:     e.CarrierRef.String,
+			Content:        e.Content,
+		}
+		if e.ValidUntil.Valid {
+			item.ValidUntil = e.ValidUntil.Time.Format("2006-01-02")
+		}
+		items = append(items, item)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{"holon_id": holonID, "evidence": items}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// projectResourceURI qualifies a resource URI with the root of its project
+func projectResourceURI(uri, root string) string {
+	return uri + "?" + resourceProjectParam + "=" + url.QueryEscape(root)
+}
+
+// splitResourceURI separates a resource URI from its project, which is empty for the session's default project
+func splitResourceURI(uri string) (string, string) {
+	base, query, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri, ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return uri, ""
+	}
+	return base, values.Get(resourceProjectParam)
+}
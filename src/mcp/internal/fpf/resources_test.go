@@ -0,0 +1,92 @@
+package fpf
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResources_ListAndRead(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	ctx := context.Background()
+	fsm.State.Phase = PhaseAbduction
+
+	if _, err := tools.RecordContext("Cache: Redis cluster.", "1. Latency under 50ms."); err != nil {
+		t.Fatalf("RecordContext failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis(ctx, "Use Redis", "Cache sessions in Redis.", "global", "system", "Fast", "", nil, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-redis", "use-redis", "test", "Load test ok", "pass", "L1", "test-runner", "2099-12-31"); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+	if err := tools.DB.CreateHolon(ctx, "cache-decision", "DRR", "", "DRR", "Cache Decision", "Chose Redis.", "default", "", "use-redis"); err != nil {
+		t.Fatalf("CreateHolon failed: %v", err)
+	}
+
+	resources, err := tools.ListResources(ctx)
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+	uris := make(map[string]bool)
+	for _, r := range resources {
+		uris[r.URI] = true
+	}
+	for _, want := range []string{"quint://context", "quint://holon/use-redis", "quint://drr/cache-decision"} {
+		if !uris[want] {
+			t.Errorf("Expected %s in resources/list, got %v", want, uris)
+		}
+	}
+
+	holon, err := tools.ReadResource(ctx, "quint://holon/use-redis")
+	if err != nil {
+		t.Fatalf("ReadResource(holon) failed: %v", err)
+	}
+	if !strings.Contains(holon.Text, "content_hash:") || !strings.Contains(holon.Text, "Cache sessions in Redis.") {
+		t.Errorf("Expected the projection file, got: %s", holon.Text)
+	}
+
+	drr, err := tools.ReadResource(ctx, "quint://drr/cache-decision")
+	if err != nil {
+		t.Fatalf("ReadResource(drr) failed: %v", err)
+	}
+	if !strings.Contains(drr.Text, "Chose Redis.") {
+		t.Errorf("Expected DRR rendered from DB, got: %s", drr.Text)
+	}
+
+	evidence, err := tools.ReadResource(ctx, "quint://evidence/use-redis")
+	if err != nil {
+		t.Fatalf("ReadResource(evidence) failed: %v", err)
+	}
+	if evidence.MimeType != "application/json" || !strings.Contains(evidence.Text, `"id": "e-redis"`) {
+		t.Errorf("Expected evidence JSON, got: %s", evidence.Text)
+	}
+
+	boundedContext, err := tools.ReadResource(ctx, "quint://context")
+	if err != nil || !strings.Contains(boundedContext.Text, "Redis cluster") {
+		t.Errorf("Expected context.md, got: %v %s", err, boundedContext.Text)
+	}
+}
+
+func TestResources_NotFound(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	if err := tools.DB.CreateHolon(context.Background(), "some-drr", "DRR", "", "DRR", "Some DRR", "Body", "default", "", ""); err != nil {
+		t.Fatalf("CreateHolon failed: %v", err)
+	}
+
+	for _, uri := range []string{
+		"quint://holon/missing",
+		"quint://holon/some-drr", // DRRs live under quint://drr/
+		"quint://holon/../context",
+		"quint://evidence/missing",
+		"quint://context", // not recorded yet
+		"file:///etc/passwd",
+	} {
+		_, err := tools.ReadResource(t.Context(), uri)
+		var notFound *ErrResourceNotFound
+		if !errors.As(err, &notFound) {
+			t.Errorf("%s: expected ErrResourceNotFound, got %v", uri, err)
+		}
+	}
+}
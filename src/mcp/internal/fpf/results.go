@@ -0,0 +1,489 @@
+package fpf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Structured tool results. Each is served as MCP structuredContent (its JSON Schema is the
+// tool's outputSchema) and rendered by Markdown for the human-readable text content.
+
+type PhaseResult struct {
+	Phase   string `json:"phase" schema:"required" description:"Current FPF phase"`
+	message string
+}
+
+func (r *PhaseResult) Markdown() string {
+	if r.message != "" {
+		return r.message
+	}
+	return r.Phase
+}
+
+// ChangeResult reports a state change to a holon or the bounded context
+type ChangeResult struct {
+	HolonID string `json:"holon_id,omitempty" description:"Affected holon"`
+	Layer   string `json:"layer,omitempty" description:"Layer of the holon after the change"`
+	Path    string `json:"path,omitempty" description:"File written by the change"`
+	Message string `json:"message,omitempty"`
+}
+
+func (r *ChangeResult) Markdown() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return r.Path
+}
+
+type DecisionResult struct {
+	DRRID       string   `json:"drr_id" schema:"required"`
+	Path        string   `json:"path" schema:"required" description:"DRR file"`
+	WinnerID    string   `json:"winner_id" schema:"required"`
+	RejectedIDs []string `json:"rejected_ids,omitempty"`
+}
+
+func (r *DecisionResult) Markdown() string {
+	return r.Path
+}
+
+type ActualizeResult struct {
+	LastCommit string `json:"last_commit,omitempty" description:"Baseline commit after reconciliation"`
+	Report     string `json:"report" schema:"required" description:"Migration and reconciliation log"`
+}
+
+func (r *ActualizeResult) Markdown() string {
+	return r.Report
+}
+
+type IntervalResult struct {
+	Lower       float64 `json:"lower"`
+	Upper       float64 `json:"upper"`
+	Credibility float64 `json:"credibility"`
+}
+
+type WaiverRef struct {
+	EvidenceID string `json:"evidence_id"`
+	HolonID    string `json:"holon_id"`
+	Until      string `json:"until"`
+}
+
+// ReliabilityResult is the R_eff breakdown for one holon
+type ReliabilityResult struct {
+	HolonID       string         `json:"holon_id" schema:"required"`
+	R             float64        `json:"r_eff" schema:"required" description:"Effective reliability (WLNK over dependencies)"`
+	Interval      IntervalResult `json:"interval" schema:"required" description:"Credible interval of r_eff"`
+	SelfScore     float64        `json:"self_score" schema:"required"`
+	Formality     int            `json:"formality" schema:"required" description:"Effective F level"`
+	SelfFormality int            `json:"self_formality"`
+	WeakestLink   string         `json:"weakest_link,omitempty" description:"Dependency capping r_eff; empty when the holon's own evidence does"`
+	Scope         string         `json:"scope" schema:"required" description:"Effective scope (G)"`
+	DecayPenalty  float64        `json:"decay_penalty"`
+	Waivers       []WaiverRef    `json:"waivers,omitempty"`
+	PolicyVersion string         `json:"policy_version"`
+	PenaltyMode   string         `json:"penalty_mode"`
+	Factors       []string       `json:"factors,omitempty"`
+}
+
+func (r *ReliabilityResult) Markdown() string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("## Reliability Report: %s\n\n", r.HolonID))
+	result.WriteString(fmt.Sprintf("**R_eff: %.2f**\n", r.R))
+	result.WriteString(fmt.Sprintf("- Credible Interval (%.0f%%): [%.2f, %.2f]\n", r.Interval.Credibility*100, r.Interval.Lower, r.Interval.Upper))
+	result.WriteString(fmt.Sprintf("- Self Score: %.2f\n", r.SelfScore))
+	if r.Formality < r.SelfFormality {
+		result.WriteString(fmt.Sprintf("- Formality: F%d (self: F%d)\n", r.Formality, r.SelfFormality))
+	} else {
+		result.WriteString(fmt.Sprintf("- Formality: F%d\n", r.Formality))
+	}
+	if r.WeakestLink != "" {
+		result.WriteString(fmt.Sprintf("- Weakest Link: %s\n", r.WeakestLink))
+	}
+	result.WriteString(fmt.Sprintf("- Scope (G): %s\n", r.Scope))
+	if r.DecayPenalty > 0 {
+		result.WriteString(fmt.Sprintf("- Decay Penalty: %.2f\n", r.DecayPenalty))
+	}
+	for _, w := range r.Waivers {
+		result.WriteString(fmt.Sprintf("- Waived: %s (%s) until %s\n", w.EvidenceID, w.HolonID, w.Until))
+	}
+	result.WriteString(fmt.Sprintf("- Policy: %s (%s penalty)\n", r.PolicyVersion, r.PenaltyMode))
+	if len(r.Factors) > 0 {
+		result.WriteString("\n**Factors:**\n")
+		for _, f := range r.Factors {
+			result.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+	}
+	return result.String()
+}
+
+// AuditNode is one holon in an audit tree, listed depth-first
+type AuditNode struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Parent    string   `json:"parent,omitempty"`
+	Relation  string   `json:"relation" schema:"enum=root|componentOf|memberOf" description:"How the node hangs under its parent; memberOf does not propagate R"`
+	CL        int      `json:"cl,omitempty" description:"Congruence level of the componentOf edge"`
+	Depth     int      `json:"depth"`
+	R         float64  `json:"r_eff"`
+	Formality int      `json:"formality"`
+	Factors   []string `json:"factors,omitempty"`
+}
+
+type AuditTreeResult struct {
+	RootID string      `json:"root_id" schema:"required"`
+	Nodes  []AuditNode `json:"nodes,omitempty"`
+	text   string
+}
+
+func (r *AuditTreeResult) Markdown() string {
+	return r.text
+}
+
+type UnspecifiedDimension struct {
+	Dimension string   `json:"dimension"`
+	Allowed   []string `json:"allowed"`
+}
+
+// ScopeResult tells whether a holon's effective scope covers a context slice
+type ScopeResult struct {
+	HolonID      string                 `json:"holon_id" schema:"required"`
+	TargetID     string                 `json:"target_id" schema:"required" description:"Holon whose scope was checked: the selected hypothesis for a DRR"`
+	Applies      string                 `json:"applies" schema:"required,enum=yes|no|conditional"`
+	Scope        string                 `json:"effective_scope" schema:"required"`
+	Slice        string                 `json:"slice" schema:"required"`
+	OutsideScope []string               `json:"outside_scope,omitempty" description:"dim=value pairs of the slice outside the scope"`
+	Unspecified  []UnspecifiedDimension `json:"unspecified,omitempty" description:"Dimensions the scope constrains but the slice leaves open"`
+	EmptyScope   bool                   `json:"empty_scope"`
+}
+
+func (r *ScopeResult) Markdown() string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("## Scope Check: %s\n\n", r.HolonID))
+	if r.TargetID != r.HolonID {
+		result.WriteString(fmt.Sprintf("Decision selects **%s**; checking its scope.\n\n", r.TargetID))
+	}
+	result.WriteString(fmt.Sprintf("**Applies: %s**\n", strings.ToUpper(r.Applies)))
+	result.WriteString(fmt.Sprintf("- Effective scope (G): %s\n", r.Scope))
+	result.WriteString(fmt.Sprintf("- Slice: %s\n", r.Slice))
+	for _, m := range r.OutsideScope {
+		result.WriteString(fmt.Sprintf("- Outside scope: %s\n", m))
+	}
+	for _, d := range r.Unspecified {
+		result.WriteString(fmt.Sprintf("- Unspecified: %s (scope allows %s)\n", d.Dimension, strings.Join(d.Allowed, ", ")))
+	}
+	if r.EmptyScope {
+		result.WriteString("\n⚠️ Effective scope is empty: dependencies admit no common context slice.\n")
+	}
+	return result.String()
+}
+
+type ScoreSnapshot struct {
+	R            float64 `json:"r_eff"`
+	SelfScore    float64 `json:"self_score"`
+	WeakestLink  string  `json:"weakest_link,omitempty"`
+	DecayPenalty float64 `json:"decay_penalty"`
+}
+
+type SensitivityEntry struct {
+	Change      string  `json:"change"`
+	R           float64 `json:"r_eff"`
+	Delta       float64 `json:"delta"`
+	WeakestLink string  `json:"weakest_link,omitempty"`
+}
+
+// WhatIfResult is a simulation (with changes) or a sensitivity sweep (without)
+type WhatIfResult struct {
+	HolonID     string             `json:"holon_id" schema:"required"`
+	Changes     []string           `json:"changes,omitempty"`
+	Before      ScoreSnapshot      `json:"before" schema:"required" description:"Current scores"`
+	After       *ScoreSnapshot     `json:"after,omitempty" description:"Scores after the changes"`
+	Delta       float64            `json:"delta"`
+	Sensitivity []SensitivityEntry `json:"sensitivity,omitempty" description:"Single changes ranked by |delta|, when no changes were given"`
+}
+
+func (r *WhatIfResult) Markdown() string {
+	var result strings.Builder
+	if r.After == nil {
+		result.WriteString(fmt.Sprintf("## Sensitivity: %s\n\n", r.HolonID))
+		result.WriteString(fmt.Sprintf("**R_eff: %.2f**", r.Before.R))
+		if r.Before.WeakestLink != "" {
+			result.WriteString(fmt.Sprintf(" (weakest link: %s)", r.Before.WeakestLink))
+		}
+		result.WriteString("\n\n")
+		if len(r.Sensitivity) == 0 {
+			result.WriteString("No single change moves R_eff.\n")
+		} else {
+			result.WriteString("| Change | R_eff | Δ | Weakest Link |\n")
+			result.WriteString("|--------|-------|---|--------------|\n")
+			for _, s := range r.Sensitivity {
+				result.WriteString(fmt.Sprintf("| %s | %.2f | %+.2f | %s |\n", s.Change, s.R, s.Delta, weakestLinkOrSelf(s.WeakestLink)))
+			}
+		}
+		result.WriteString("\n_Simulation only; nothing was persisted._\n")
+		return result.String()
+	}
+
+	result.WriteString(fmt.Sprintf("## What-If: %s\n\n", r.HolonID))
+	result.WriteString("**Changes:**\n")
+	for _, ch := range r.Changes {
+		result.WriteString(fmt.Sprintf("- %s\n", ch))
+	}
+	result.WriteString(fmt.Sprintf("\n**R_eff: %.2f → %.2f (Δ %+.2f)**\n", r.Before.R, r.After.R, r.Delta))
+	result.WriteString(fmt.Sprintf("- Weakest Link: %s → %s\n", weakestLinkOrSelf(r.Before.WeakestLink), weakestLinkOrSelf(r.After.WeakestLink)))
+	result.WriteString(fmt.Sprintf("- Self Score: %.2f → %.2f\n", r.Before.SelfScore, r.After.SelfScore))
+	if r.Before.DecayPenalty != r.After.DecayPenalty {
+		result.WriteString(fmt.Sprintf("- Decay Penalty: %.2f → %.2f\n", r.Before.DecayPenalty, r.After.DecayPenalty))
+	}
+	result.WriteString("\n_Simulation only; nothing was persisted._\n")
+	return result.String()
+}
+
+func weakestLinkOrSelf(id string) string {
+	if id == "" {
+		return "(self)"
+	}
+	return id
+}
+
+type DanglingRelationRef struct {
+	SourceID string `json:"source_id"`
+	Relation string `json:"relation"`
+	TargetID string `json:"target_id"`
+	Missing  string `json:"missing" schema:"enum=source|target|both"`
+}
+
+type OrphanedEvidenceRef struct {
+	EvidenceID string `json:"evidence_id"`
+	HolonID    string `json:"holon_id"`
+}
+
+type GraphCheckResult struct {
+	Holons            int                   `json:"holons"`
+	Relations         int                   `json:"relations"`
+	Evidence          int                   `json:"evidence"`
+	OK                bool                  `json:"ok" schema:"required"`
+	Cycles            [][]string            `json:"cycles,omitempty" description:"Strongly connected components (sorted holon IDs)"`
+	DanglingRelations []DanglingRelationRef `json:"dangling_relations,omitempty"`
+	OrphanedEvidence  []OrphanedEvidenceRef `json:"orphaned_evidence,omitempty"`
+}
+
+func (r *GraphCheckResult) Markdown() string {
+	var result strings.Builder
+	result.WriteString("## Graph Integrity Report\n\n")
+	result.WriteString(fmt.Sprintf("Checked %d holons, %d relations, %d evidence.\n\n", r.Holons, r.Relations, r.Evidence))
+
+	if r.OK {
+		result.WriteString("### Graph OK ✓\n\nNo cycles, dangling relations or orphaned evidence.\n")
+		return result.String()
+	}
+
+	if len(r.Cycles) > 0 {
+		result.WriteString(fmt.Sprintf("### CYCLES (%d)\n\n", len(r.Cycles)))
+		result.WriteString("R_eff inside a cycle is reduced by the cycle penalty. Remove one relation to break each cycle.\n\n")
+		for _, cycle := range r.Cycles {
+			result.WriteString(fmt.Sprintf("- %s\n", strings.Join(cycle, " ↔ ")))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(r.DanglingRelations) > 0 {
+		result.WriteString(fmt.Sprintf("### DANGLING RELATIONS (%d)\n\n", len(r.DanglingRelations)))
+		result.WriteString("| Source | Relation | Target | Missing |\n")
+		result.WriteString("|--------|----------|--------|---------|\n")
+		for _, d := range r.DanglingRelations {
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", d.SourceID, d.Relation, d.TargetID, d.Missing))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(r.OrphanedEvidence) > 0 {
+		result.WriteString(fmt.Sprintf("### ORPHANED EVIDENCE (%d)\n\n", len(r.OrphanedEvidence)))
+		for _, e := range r.OrphanedEvidence {
+			result.WriteString(fmt.Sprintf("- %s → missing holon %s\n", e.EvidenceID, e.HolonID))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// QueryMatch is one holon matched by quint_query
+type QueryMatch struct {
+	ID        string  `json:"id"`
+	Title     string  `json:"title"`
+	Layer     string  `json:"layer"`
+	Kind      string  `json:"kind,omitempty"`
+	R         float64 `json:"r_eff"`
+	Formality int     `json:"formality" description:"Effective F level"`
+	Scope     string  `json:"scope" description:"Effective scope (G)"`
+	Updated   string  `json:"updated,omitempty" description:"Day of the last change (YYYY-MM-DD)"`
+}
+
+type QueryResult struct {
+	Query  string       `json:"query" schema:"required"`
+	Total  int          `json:"total" schema:"required" description:"Number of matches before the limit"`
+	Holons []QueryMatch `json:"holons" schema:"required"`
+}
+
+func (r *QueryResult) Markdown() string {
+	if r.Total == 0 {
+		return fmt.Sprintf("No holons match `%s`.", r.Query)
+	}
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("## Query: `%s`\n\n", r.Query))
+	if len(r.Holons) < r.Total {
+		result.WriteString(fmt.Sprintf("Matches: %d (showing %d)\n\n", r.Total, len(r.Holons)))
+	} else {
+		result.WriteString(fmt.Sprintf("Matches: %d\n\n", r.Total))
+	}
+	result.WriteString("| ID | Title | Layer | Kind | R_eff | F | Scope | Updated |\n")
+	result.WriteString("|----|-------|-------|------|-------|---|-------|---------|\n")
+	for _, h := range r.Holons {
+		result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %.2f | F%d | %s | %s |\n", h.ID, h.Title, h.Layer, h.Kind, h.R, h.Formality, h.Scope, h.Updated))
+	}
+	return result.String()
+}
+
+// SearchHit is one full-text match, linked to the holon it belongs to
+type SearchHit struct {
+	HolonID    string  `json:"holon_id"`
+	Title      string  `json:"title"`
+	Layer      string  `json:"layer,omitempty"`
+	Source     string  `json:"source" schema:"enum=holon|drr|evidence" description:"What matched: the holon, the DRR or evidence attached to the holon"`
+	EvidenceID string  `json:"evidence_id,omitempty"`
+	Snippet    string  `json:"snippet" description:"Matching fragment with matched terms in **bold**"`
+	Score      float64 `json:"score" description:"BM25 relevance, higher is better"`
+	URI        string  `json:"uri" description:"Resource URI of the holon or DRR"`
+}
+
+type SearchResult struct {
+	Query string      `json:"query" schema:"required"`
+	Hits  []SearchHit `json:"hits" schema:"required" description:"Matches, best first"`
+}
+
+func (r *SearchResult) Markdown() string {
+	if len(r.Hits) == 0 {
+		return fmt.Sprintf("No matches for `%s`.", r.Query)
+	}
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("## Search: `%s`\n\n", r.Query))
+	for i, h := range r.Hits {
+		source := h.Source
+		if h.EvidenceID != "" {
+			source = "evidence " + h.EvidenceID
+		}
+		result.WriteString(fmt.Sprintf("%d. **%s** (%s, %s) — %s\n", i+1, h.Title, h.HolonID, h.Layer, source))
+		result.WriteString(fmt.Sprintf("   %s\n", strings.Join(strings.Fields(h.Snippet), " ")))
+	}
+	return result.String()
+}
+
+type StaleEvidence struct {
+	EvidenceID  string `json:"evidence_id"`
+	Type        string `json:"type"`
+	DaysOverdue int    `json:"days_overdue"`
+}
+
+type StaleHolon struct {
+	HolonID  string          `json:"holon_id"`
+	Title    string          `json:"title"`
+	Layer    string          `json:"layer"`
+	Evidence []StaleEvidence `json:"evidence"`
+}
+
+type DecayingHolon struct {
+	HolonID        string `json:"holon_id"`
+	Title          string `json:"title"`
+	DaysUntilBelow int    `json:"days_until_below" description:"Days until R_eff falls below the assurance threshold"`
+}
+
+type ActiveWaiver struct {
+	EvidenceID      string `json:"evidence_id"`
+	HolonID         string `json:"holon_id"`
+	HolonTitle      string `json:"holon_title,omitempty"`
+	WaivedUntil     string `json:"waived_until"`
+	WaivedBy        string `json:"waived_by"`
+	Rationale       string `json:"rationale"`
+	DaysUntilExpiry int    `json:"days_until_expiry"`
+}
+
+type LayerMove struct {
+	HolonID string `json:"holon_id"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// DecayResult is the freshness report, or the outcome of a deprecation or waiver
+type DecayResult struct {
+	Action       string          `json:"action" schema:"required,enum=report|deprecate|waive"`
+	Stale        []StaleHolon    `json:"stale,omitempty" description:"Holons with expired, unwaived evidence"`
+	Decaying     []DecayingHolon `json:"decaying,omitempty" description:"L2 holons projected to fall below the threshold within forecast_days"`
+	Threshold    float64         `json:"threshold,omitempty"`
+	ForecastDays int             `json:"forecast_days,omitempty"`
+	Waivers      []ActiveWaiver  `json:"waivers,omitempty" description:"Active waivers"`
+	Deprecated   *LayerMove      `json:"deprecated,omitempty"`
+	Waiver       *ActiveWaiver   `json:"waiver,omitempty" description:"Waiver recorded by this call"`
+}
+
+func (r *DecayResult) Markdown() string {
+	switch r.Action {
+	case "deprecate":
+		d := r.Deprecated
+		return fmt.Sprintf("Deprecated: %s %s → %s\n\nThis decision now requires re-evaluation.\nNext step: Run /q1-hypothesize to explore alternatives.", d.HolonID, d.From, d.To)
+	case "waive":
+		w := r.Waiver
+		return fmt.Sprintf(`Waiver recorded:
+- Evidence: %s
+- Waived until: %s
+- Rationale: %s
+
+⚠️ This evidence returns to EXPIRED status after %s.
+   Set a reminder to run /q3-validate before then.`, w.EvidenceID, w.WaivedUntil, w.Rationale, w.WaivedUntil)
+	}
+
+	var result strings.Builder
+	result.WriteString("## Evidence Freshness Report\n\n")
+
+	if len(r.Stale) == 0 {
+		result.WriteString("### All holons FRESH ✓\n\nNo expired evidence found.\n")
+	} else {
+		result.WriteString(fmt.Sprintf("### STALE (%d holons require action)\n\n", len(r.Stale)))
+
+		for _, h := range r.Stale {
+			result.WriteString(fmt.Sprintf("#### %s (%s)\n", h.Title, h.Layer))
+			result.WriteString("| ID | Type | Status | Details |\n")
+			result.WriteString("|-----|------|--------|--------|\n")
+			for _, item := range h.Evidence {
+				result.WriteString(fmt.Sprintf("| %s | %s | EXPIRED | %d days overdue |\n", item.EvidenceID, item.Type, item.DaysOverdue))
+			}
+			result.WriteString("\nActions:\n")
+			result.WriteString(fmt.Sprintf("  → /q3-validate %s (refresh)\n", h.HolonID))
+			result.WriteString(fmt.Sprintf("  → /q-decay --deprecate %s (downgrade)\n", h.HolonID))
+			result.WriteString("  → /q-decay --waive <evidence_id> --until <date> --rationale \"...\"\n\n")
+		}
+	}
+
+	if len(r.Decaying) > 0 {
+		result.WriteString(fmt.Sprintf("---\n\n### DECAYING (R_eff drops below %.2f within %d days)\n\n", r.Threshold, r.ForecastDays))
+		result.WriteString("| Holon | ID | Below threshold in |\n")
+		result.WriteString("|-------|----|--------------------|\n")
+		for _, d := range r.Decaying {
+			result.WriteString(fmt.Sprintf("| %s | %s | %d days |\n", d.Title, d.HolonID, d.DaysUntilBelow))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(r.Waivers) > 0 {
+		result.WriteString("---\n\n### WAIVED (temporary risk acceptance)\n\n")
+		result.WriteString("| Holon | Evidence | Waived Until | By | Rationale |\n")
+		result.WriteString("|-------|----------|--------------|----|-----------|\n")
+		for _, w := range r.Waivers {
+			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", w.HolonTitle, w.EvidenceID, w.WaivedUntil, w.WaivedBy, w.Rationale))
+		}
+		for _, w := range r.Waivers {
+			if w.DaysUntilExpiry <= 30 {
+				result.WriteString(fmt.Sprintf("\n⚠️ Waiver for %s expires in %d days\n", w.EvidenceID, w.DaysUntilExpiry))
+			}
+		}
+	}
+
+	return result.String()
+}
@@ -0,0 +1,127 @@
+package fpf
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// callStructured calls a tool through the server and checks its structuredContent against the outputSchema
+func callStructured(t *testing.T, server *Server, name, arguments string) map[string]interface{} {
+	t.Helper()
+	resp := server.Handle(t.Context(), JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"` + name + `","arguments":` + arguments + `}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("%s: %+v", name, resp.Error)
+	}
+	result := resp.Result.(CallToolResult)
+	if result.IsError {
+		t.Fatalf("%s returned error: %s", name, result.Content[0].Text)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text == "" {
+		t.Errorf("%s: expected markdown text content, got %+v", name, result.Content)
+	}
+
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("%s: marshal structuredContent: %v", name, err)
+	}
+	var structured map[string]interface{}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		t.Fatalf("%s: structuredContent is not an object: %s", name, data)
+	}
+	tool, _ := lookupTool(name)
+	if errs := tool.output.Validate(structured); len(errs) > 0 {
+		t.Errorf("%s: structuredContent does not match outputSchema: %+v\n%s", name, errs, data)
+	}
+	return structured
+}
+
+func TestStructuredResults(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	ctx := context.Background()
+	fsm.State.Phase = PhaseAbduction
+
+	if err := tools.DB.CreateHolon(ctx, "base", "hypothesis", "system", "L2", "Base", "Content", "default", "env=prod", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.CreateHolon(ctx, "top", "hypothesis", "system", "L2", "Top", "Content", "default", "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.CreateRelation(ctx, "base", "componentOf", "top", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-top", "top", "test", "ok", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tools.DB.AddEvidence(ctx, "e-base", "base", "test", "old", "pass", "L2", "test-runner", "2020-01-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := callStructured(t, server, "quint_calculate_r", `{"holon_id":"top"}`)
+	if r["holon_id"] != "top" || r["weakest_link"] != "base" {
+		t.Errorf("Expected weakest link base for top, got %+v", r)
+	}
+	if interval, ok := r["interval"].(map[string]interface{}); !ok || interval["credibility"] != 0.9 {
+		t.Errorf("Expected interval with credibility 0.9, got %+v", r["interval"])
+	}
+
+	tree := callStructured(t, server, "quint_audit_tree", `{"holon_id":"top"}`)
+	nodes, _ := tree["nodes"].([]interface{})
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 audit nodes, got %+v", tree)
+	}
+	child := nodes[1].(map[string]interface{})
+	if child["id"] != "base" || child["parent"] != "top" || child["cl"] != 2.0 || child["relation"] != "componentOf" {
+		t.Errorf("Unexpected dependency node: %+v", child)
+	}
+
+	decay := callStructured(t, server, "quint_check_decay", `{}`)
+	stale, _ := decay["stale"].([]interface{})
+	if len(stale) != 1 || stale[0].(map[string]interface{})["holon_id"] != "base" {
+		t.Errorf("Expected base as the only stale holon, got %+v", decay)
+	}
+
+	scope := callStructured(t, server, "quint_check_scope", `{"holon_id":"top","slice":"env=dev"}`)
+	if scope["applies"] != "no" {
+		t.Errorf("Expected applies=no for env=dev, got %+v", scope)
+	}
+
+	whatIf := callStructured(t, server, "quint_what_if", `{"holon_id":"top"}`)
+	if _, ok := whatIf["sensitivity"].([]interface{}); !ok {
+		t.Errorf("Expected a sensitivity list, got %+v", whatIf)
+	}
+
+	graph := callStructured(t, server, "quint_check_graph", `{}`)
+	if graph["ok"] != true || graph["holons"] != 2.0 {
+		t.Errorf("Expected a clean graph of 2 holons, got %+v", graph)
+	}
+
+	proposed := callStructured(t, server, "quint_propose", `{"title":"New Idea","content":"c","scope":"global","kind":"system","rationale":"{}"}`)
+	if proposed["holon_id"] != "new-idea" || proposed["layer"] != "L0" {
+		t.Errorf("Expected new-idea in L0, got %+v", proposed)
+	}
+
+	status := callStructured(t, server, "quint_status", `{}`)
+	if status["phase"] != string(PhaseAbduction) {
+		t.Errorf("Expected phase ABDUCTION, got %+v", status)
+	}
+}
+
+func TestToolsList_OutputSchemas(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	resp := NewServer(tools).Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+
+	list := resp.Result.(map[string]interface{})["tools"].([]Tool)
+	for _, tool := range list {
+		schema, ok := tool.OutputSchema.(*Schema)
+		if !ok || schema.Type != "object" || len(schema.Properties) == 0 {
+			t.Errorf("%s: expected an object outputSchema, got %+v", tool.Name, tool.OutputSchema)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package fpf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used for tool inputs. It is generated from the
+// tool's input struct: the json tag names the property, the description tag documents it,
+// and the schema tag holds constraints, e.g. `schema:"required,enum=PASS|FAIL,minimum=0,maximum=9,default=3"`.
+type Schema struct {
+	Type                 string             `json:"type"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// MarshalJSON always emits "properties" for objects; some clients reject object schemas without it
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	if s.Type != "object" {
+		return json.Marshal((*plain)(s))
+	}
+	properties := s.Properties
+	if properties == nil {
+		properties = map[string]*Schema{}
+	}
+	return json.Marshal(struct {
+		*plain
+		Properties map[string]*Schema `json:"properties"`
+	}{(*plain)(s), properties})
+}
+
+// FieldError describes one argument that does not match the tool's input schema
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ErrInvalidArguments lists every argument that failed validation
+type ErrInvalidArguments struct {
+	Fields []FieldError
+}
+
+func (e *ErrInvalidArguments) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "Invalid params: " + strings.Join(msgs, "; ")
+}
+
+// schemaFor generates the schema of a Go type. Structs become closed objects.
+func schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Struct:
+		closed := false
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: &closed}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := jsonName(field)
+			if name == "" {
+				continue
+			}
+			prop := schemaFor(field.Type)
+			prop.Description = field.Tag.Get("description")
+			if applyConstraints(prop, field.Tag.Get("schema")) {
+				schema.Required = append(schema.Required, name)
+			}
+			schema.Properties[name] = prop
+		}
+		return schema
+	}
+	panic(fmt.Sprintf("schemaFor: unsupported type %s", t))
+}
+
+// jsonName returns the property name of an exported struct field, or "" if it is not serialized
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// applyConstraints parses a schema tag into prop and reports whether the field is required
+func applyConstraints(prop *Schema, tag string) bool {
+	required := false
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "":
+		case "required":
+			required = true
+		case "enum":
+			prop.Enum = strings.Split(value, "|")
+		case "minimum":
+			prop.Minimum = parseBound(value)
+		case "maximum":
+			prop.Maximum = parseBound(value)
+		case "default":
+			prop.Default = parseDefault(prop.Type, value)
+		default:
+			panic(fmt.Sprintf("schema tag: unknown constraint %q", key))
+		}
+	}
+	return required
+}
+
+func parseBound(value string) *float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("schema tag: invalid bound %q", value))
+	}
+	return &f
+}
+
+func parseDefault(typ, value string) interface{} {
+	switch typ {
+	case "integer", "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			panic(fmt.Sprintf("schema tag: invalid default %q", value))
+		}
+		return f
+	case "boolean":
+		return value == "true"
+	}
+	return value
+}
+
+// Validate checks decoded JSON arguments against the schema and returns every mismatch, sorted by field
+func (s *Schema) Validate(args map[string]interface{}) []FieldError {
+	var value interface{} = args
+	if args == nil {
+		value = map[string]interface{}{}
+	}
+	errs := s.validate("", value)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func (s *Schema) validate(path string, value interface{}) []FieldError {
+	fail := func(format string, a ...interface{}) []FieldError {
+		return []FieldError{{Field: path, Message: fmt.Sprintf(format, a...)}}
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("must be an object")
+		}
+		var errs []FieldError
+		for _, name := range s.Required {
+			if v, ok := obj[name]; !ok || v == nil {
+				errs = append(errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+			}
+		}
+		for name, v := range obj {
+			prop, known := s.Properties[name]
+			if !known {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs = append(errs, FieldError{Field: joinPath(path, name), Message: "is not a known argument"})
+				}
+				continue
+			}
+			if v == nil {
+				continue // Explicit null is treated as omitted
+			}
+			errs = append(errs, prop.validate(joinPath(path, name), v)...)
+		}
+		return errs
+
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fail("must be an array")
+		}
+		var errs []FieldError
+		for i, v := range arr {
+			errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), v)...)
+		}
+		return errs
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok {
+			return fail("must be a number")
+		}
+		if s.Type == "integer" && num != math.Trunc(num) {
+			return fail("must be an integer")
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			return fail("must be at least %g", *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			return fail("must be at most %g", *s.Maximum)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+	}
+	return nil
+}
+
+// decodeArgs fills in schema defaults for omitted top-level arguments and decodes them into out.
+// Arguments must already be validated.
+func (s *Schema) decodeArgs(args map[string]interface{}, out interface{}) error {
+	merged := make(map[string]interface{}, len(s.Properties))
+	for name, prop := range s.Properties {
+		if prop.Default != nil {
+			merged[name] = prop.Default
+		}
+	}
+	for name, v := range args {
+		if v != nil {
+			merged[name] = v
+		}
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
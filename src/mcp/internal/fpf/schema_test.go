@@ -0,0 +1,187 @@
+package fpf
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type schemaFixture struct {
+	Name    string   `json:"name" schema:"required" description:"Name"`
+	Level   int      `json:"level" schema:"minimum=1,maximum=3,default=3"`
+	Verdict string   `json:"verdict" schema:"enum=PASS|FAIL"`
+	Tags    []string `json:"tags"`
+	Nested  []struct {
+		Op string `json:"op" schema:"required"`
+	} `json:"nested"`
+	internal string
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(schemaFixture{}))
+
+	if schema.Type != "object" || len(schema.Properties) != 5 {
+		t.Fatalf("Expected object with 5 properties, got %+v", schema)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"name"}) {
+		t.Errorf("Expected required [name], got %v", schema.Required)
+	}
+	level := schema.Properties["level"]
+	if level.Type != "integer" || *level.Minimum != 1 || *level.Maximum != 3 || level.Default != 3.0 {
+		t.Errorf("Unexpected level schema: %+v", level)
+	}
+	if tags := schema.Properties["tags"]; tags.Type != "array" || tags.Items.Type != "string" {
+		t.Errorf("Unexpected tags schema: %+v", tags)
+	}
+	if nested := schema.Properties["nested"]; nested.Items.Type != "object" || nested.Items.Required[0] != "op" {
+		t.Errorf("Unexpected nested schema: %+v", nested.Items)
+	}
+
+	data, err := json.Marshal(schemaFor(reflect.TypeOf(noInput{})))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"properties":{}`) {
+		t.Errorf("Expected empty properties on an object without fields, got %s", data)
+	}
+}
+
+func TestSchemaValidate_ReportsEveryField(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(schemaFixture{}))
+
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"level": 7,
+		"verdict": "MAYBE",
+		"tags": ["ok", 3],
+		"nested": [{"op": "x"}, {}],
+		"extra": true
+	}`), &args); err != nil {
+		t.Fatal(err)
+	}
+
+	got := schema.Validate(args)
+	want := []FieldError{
+		{Field: "extra", Message: "is not a known argument"},
+		{Field: "level", Message: "must be at most 3"},
+		{Field: "name", Message: "is required"},
+		{Field: "nested[1].op", Message: "is required"},
+		{Field: "tags[1]", Message: "must be a string"},
+		{Field: "verdict", Message: "must be one of PASS, FAIL"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate mismatch\n got: %+v\nwant: %+v", got, want)
+	}
+
+	if errs := schema.Validate(map[string]interface{}{"name": "x", "level": 2.5}); len(errs) != 1 || errs[0].Message != "must be an integer" {
+		t.Errorf("Expected integer error, got %+v", errs)
+	}
+	if errs := schema.Validate(map[string]interface{}{"name": "x", "verdict": nil}); len(errs) != 0 {
+		t.Errorf("Expected null optional argument to be accepted, got %+v", errs)
+	}
+}
+
+func TestSchemaDecodeArgs_AppliesDefaults(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(schemaFixture{}))
+
+	var in schemaFixture
+	if err := schema.decodeArgs(map[string]interface{}{"name": "x", "tags": []interface{}{"a"}}, &in); err != nil {
+		t.Fatalf("decodeArgs failed: %v", err)
+	}
+	if in.Name != "x" || in.Level != 3 || !reflect.DeepEqual(in.Tags, []string{"a"}) {
+		t.Errorf("Unexpected decoded input: %+v", in)
+	}
+}
+
+func TestToolsCall_InvalidArguments(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	resp := server.Handle(t.Context(), JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"quint_propose","arguments":{"title":"T","kind":"service","dependency_cl":5,"depends_on":"a"}}`),
+	})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("Expected -32602, got %+v", resp)
+	}
+	for _, field := range []string{"content", "scope", "rationale", "kind", "dependency_cl", "depends_on"} {
+		if !strings.Contains(resp.Error.Message, field+":") {
+			t.Errorf("Expected %s in error message, got %q", field, resp.Error.Message)
+		}
+	}
+	data, ok := resp.Error.Data.(map[string]interface{})
+	if !ok || len(data["fields"].([]FieldError)) != 6 {
+		t.Errorf("Expected 6 field errors in data, got %+v", resp.Error.Data)
+	}
+
+	resp = server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"quint_nope"}`)})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("Expected -32602 for unknown tool, got %+v", resp)
+	}
+}
+
+func TestToolsCall_DecodesTypedArguments(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseAbduction
+
+	call := func(id int, params string) CallToolResult {
+		t.Helper()
+		resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "tools/call", Params: json.RawMessage(params)})
+		if resp.Error != nil {
+			t.Fatalf("tools/call failed: %+v", resp.Error)
+		}
+		result := resp.Result.(CallToolResult)
+		if result.IsError {
+			t.Fatalf("tool returned error: %s", result.Content[0].Text)
+		}
+		return result
+	}
+
+	call(1, `{"name":"quint_propose","arguments":{"title":"Base","content":"c","scope":"global","kind":"system","rationale":"{}"}}`)
+	call(2, `{"name":"quint_propose","arguments":{"title":"Top","content":"c","scope":"global","kind":"system","rationale":"{}","depends_on":["base"],"dependency_cl":2,"formality":4}}`)
+
+	holon, err := tools.DB.GetHolon(t.Context(), "top")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.Formality.Int64 != 4 {
+		t.Errorf("Expected formality 4, got %d", holon.Formality.Int64)
+	}
+	deps, err := tools.DB.GetComponentsOf(t.Context(), "top")
+	if err != nil || len(deps) != 1 || deps[0].CongruenceLevel.Int64 != 2 {
+		t.Errorf("Expected one dependency on base with CL2, got %+v (err %v)", deps, err)
+	}
+}
+
+func TestCallTool_Errors(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+
+	var unknown *ErrUnknownTool
+	if _, err := tools.CallTool(t.Context(), "quint_nope", nil); !errors.As(err, &unknown) {
+		t.Errorf("Expected ErrUnknownTool, got %v", err)
+	}
+
+	var invalid *ErrInvalidArguments
+	if _, err := tools.CallTool(t.Context(), "quint_verify", map[string]interface{}{"hypothesis_id": "h"}); !errors.As(err, &invalid) || len(invalid.Fields) != 2 {
+		t.Errorf("Expected ErrInvalidArguments for checks_json and verdict, got %v", err)
+	}
+
+	fsm.State.Phase = PhaseDeduction
+	var precondition *PreconditionError
+	if _, err := tools.CallTool(t.Context(), "quint_verify", map[string]interface{}{"hypothesis_id": "h", "checks_json": "{}", "verdict": "PASS"}); !errors.As(err, &precondition) {
+		t.Errorf("Expected PreconditionError for a missing hypothesis, got %v", err)
+	}
+
+	result, err := tools.CallTool(t.Context(), "quint_status", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("quint_status failed: %v", err)
+	}
+	if got := result.Markdown(); got != string(PhaseDeduction) {
+		t.Errorf("Expected status %s, got %q", PhaseDeduction, got)
+	}
+}
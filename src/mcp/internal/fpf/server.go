@@ -2,16 +2,26 @@ package fpf
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sync"
 )
 
+// JSONRPCRequest is an incoming message: a request, a notification (no ID) or the client's
+// response to a server request (no method, a result or an error)
 type JSONRPCRequest struct {
 	JSONRPC string          `json:"jsonrpc"`
 	Method  string          `json:"method"`
-	Params  json.RawMessage `json:"params"`
+	Params  json.RawMessage `json:"params,omitempty"`
 	ID      interface{}     `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 }
 
 type JSONRPCResponse struct {
@@ -22,19 +32,23 @@ type JSONRPCResponse struct {
 }
 
 type RPCError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema interface{} `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  interface{}      `json:"inputSchema"`
+	OutputSchema interface{}      `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
 }
 
 type CallToolResult struct {
-	Content []ContentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []ContentItem `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
 }
 
 type ContentItem struct {
@@ -42,408 +56,717 @@ type ContentItem struct {
 	Text string `json:"text"`
 }
 
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type Server struct {
-	tools *Tools
+	tools    *Tools
+	prompts  *PromptLibrary
+	version  string // Reported as serverInfo.version
+	readOnly bool   // Refuse tool calls that change the knowledge base
+
+	inflightMu sync.Mutex
+	inflight   map[string]context.CancelFunc // Cancels running requests, keyed by JSON-encoded request ID
+
+	pendingMu     sync.Mutex
+	pending       map[string]chan JSONRPCRequest // Server requests awaiting the client's response
+	nextRequestID int
+
+	projectsMu   sync.Mutex
+	projectRoots []string          // Projects tool calls may select besides the server's own
+	projects     map[string]*Tools // Opened projects by root, except the server's own
+
+	listenersMu  sync.Mutex
+	listeners    map[int]func([]byte) // Notification sinks of connected transports
+	nextListener int
+
+	sessionsMu sync.Mutex
+	sessions   map[*session]bool // Connected client sessions
 }
 
+// NewServer creates a server for the tools of its own project and attaches itself as their change notifier
 func NewServer(t *Tools) *Server {
-	return &Server{tools: t}
+	s := &Server{
+		tools:    t,
+		version:  "dev",
+		sessions: make(map[*session]bool),
+		inflight: make(map[string]context.CancelFunc),
+		pending:  make(map[string]chan JSONRPCRequest),
+		projects: make(map[string]*Tools),
+	}
+	s.attach(t)
+	if t.Log != nil {
+		t.Log.SetSink(s.logMessage)
+	}
+	return s
+}
+
+// SetVersion sets the build version reported to clients on initialize
+func (s *Server) SetVersion(version string) {
+	s.version = version
+}
+
+// SetReadOnly makes the server refuse tool calls that change the knowledge base.
+// Tools that always do are left out of tools/list, and the others skip their bookkeeping writes.
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.projectsMu.Lock()
+	defer s.projectsMu.Unlock()
+	s.readOnly = readOnly
+	s.tools.ReadOnly = readOnly
+	for _, t := range s.projects {
+		t.ReadOnly = readOnly
+	}
+}
+
+// SetPrompts enables prompts/list and prompts/get, served from the given command templates
+func (s *Server) SetPrompts(prompts *PromptLibrary) {
+	s.prompts = prompts
 }
 
+// stdioWorkers is how many stdio requests are handled concurrently. Tool calls that can change a
+// project still run one at a time on it.
+const stdioWorkers = 8
+
+// Start serves newline-delimited JSON-RPC over stdio until stdin is closed
 func (s *Server) Start() {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
+	ctx := context.Background()
+	if err := s.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		s.tools.Log.Errorf(ctx, "reading stdin: %v", err)
+	}
+}
+
+// Serve reads newline-delimited JSON-RPC from r and writes responses and notifications to w.
+// Lines may be of any length. Notifications, including notifications/cancelled, are handled as
+// they arrive; requests wait for one of stdioWorkers slots without holding up the reader, so a
+// queued request can still be cancelled. Serve returns when r is exhausted and every pending
+// request has been answered.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	out := &lineWriter{w: w}
+	sess := &session{send: out.writeLine}
+	ctx = withSession(ctx, sess)
+	unsubscribe := s.subscribe(out.writeLine)
+	defer unsubscribe()
+	s.startSession(sess)
+	defer s.endSession(sess)
+
+	workers := make(chan struct{}, stdioWorkers)
+	var pending sync.WaitGroup
+	defer pending.Wait()
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var req JSONRPCRequest
+			if jsonErr := json.Unmarshal(line, &req); jsonErr != nil {
+				out.writeResponse(errorResponse(nil, -32700, "Parse error"))
+			} else if req.ID == nil || req.Method == "" {
+				s.Handle(ctx, req) // Notifications and responses are quick and must not wait behind requests
+			} else {
+				reqCtx, done := s.begin(ctx, req)
+				pending.Add(1)
+				go func() {
+					defer pending.Done()
+					defer done()
+					select {
+					case workers <- struct{}{}:
+						defer func() { <-workers }()
+					case <-reqCtx.Done():
+						return // Cancelled while queued
+					}
+					if resp := s.run(reqCtx, req); resp != nil {
+						out.writeResponse(resp)
+					}
+				}()
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
+	}
+}
 
-		var req JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			s.sendError(nil, -32700, "Parse error")
-			continue
+// Handle dispatches one JSON-RPC message. It returns nil for notifications, which get no response,
+// and for requests cancelled by notifications/cancelled or ctx before they completed.
+// Transports may call Handle concurrently; tool calls that can change a project are serialized
+// per project because Tools and the FSM keep mutable state.
+func (s *Server) Handle(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	if req.Method == "" && req.ID != nil {
+		s.handleClientResponse(req)
+		return nil
+	}
+	ctx, done := s.begin(ctx, req)
+	defer done()
+	return s.run(ctx, req)
+}
+
+// begin makes a request cancellable by notifications/cancelled from the moment it is accepted.
+// The returned function releases it.
+func (s *Server) begin(ctx context.Context, req JSONRPCRequest) (context.Context, func()) {
+	if req.ID == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	release := s.track(req.ID, cancel)
+	return ctx, func() {
+		release()
+		cancel()
+	}
+}
+
+// run dispatches a request accepted by begin; cancelled requests get no response
+func (s *Server) run(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	if ctx.Err() != nil {
+		return nil
+	}
+	resp := s.dispatch(ctx, req)
+	if resp != nil && ctx.Err() != nil {
+		return nil
+	}
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(ctx, req)
+	case "ping":
+		return resultResponse(req.ID, map[string]interface{}{})
+	case "logging/setLevel":
+		return s.handleSetLevel(ctx, req)
+	case "tools/list":
+		return s.handleToolsList(ctx, req)
+	case "tools/call":
+		return s.handleToolsCall(ctx, req)
+	case "resources/list":
+		return s.handleResourcesList(ctx, req)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(ctx, req)
+	case "resources/subscribe", "resources/unsubscribe":
+		return s.handleResourcesSubscribe(ctx, req)
+	case "prompts/list":
+		return s.handlePromptsList(req)
+	case "prompts/get":
+		return s.handlePromptsGet(req)
+	case "notifications/initialized", "notifications/roots/list_changed":
+		s.refreshRoots(ctx)
+		return nil
+	case "notifications/cancelled":
+		s.handleCancelled(req)
+		return nil
+	default:
+		if req.ID != nil {
+			return errorResponse(req.ID, -32601, "Method not found")
 		}
+		return nil
+	}
+}
 
-		switch req.Method {
-		case "initialize":
-			s.handleInitialize(req)
-		case "tools/list":
-			s.handleToolsList(req)
-		case "tools/call":
-			s.handleToolsCall(req)
-		case "notifications/initialized":
-			// No-op
-		default:
-			if req.ID != nil {
-				s.sendError(req.ID, -32601, "Method not found")
-			}
+// track registers the cancel function of a running request and returns its release function
+func (s *Server) track(id interface{}, cancel context.CancelFunc) func() {
+	key, err := json.Marshal(id)
+	if err != nil {
+		return func() {}
+	}
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	s.inflight[string(key)] = cancel
+	return func() {
+		s.inflightMu.Lock()
+		defer s.inflightMu.Unlock()
+		delete(s.inflight, string(key))
+	}
+}
+
+// request sends a request to the client of sess and waits for its result
+func (s *Server) request(ctx context.Context, sess *session, method string, params interface{}) (json.RawMessage, error) {
+	sess.mu.Lock()
+	send := sess.send
+	sess.mu.Unlock()
+	if send == nil {
+		return nil, fmt.Errorf("client has no open stream for %s", method)
+	}
+
+	s.pendingMu.Lock()
+	s.nextRequestID++
+	id := fmt.Sprintf("quint-%d", s.nextRequestID)
+	key, _ := json.Marshal(id)
+	response := make(chan JSONRPCRequest, 1)
+	s.pending[string(key)] = response
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, string(key))
+		s.pendingMu.Unlock()
+	}()
+
+	data, err := json.Marshal(struct {
+		JSONRPC string      `json:"jsonrpc"`
+		ID      string      `json:"id"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+	}{"2.0", id, method, params})
+	if err != nil {
+		return nil, err
+	}
+	send(data)
+
+	select {
+	case resp := <-response:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", method, resp.Error.Message)
 		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handleClientResponse delivers the client's response to a pending server request; others are ignored
+func (s *Server) handleClientResponse(resp JSONRPCRequest) {
+	key, err := json.Marshal(resp.ID)
+	if err != nil {
+		return
+	}
+	s.pendingMu.Lock()
+	response, ok := s.pending[string(key)]
+	s.pendingMu.Unlock()
+	if ok {
+		response <- resp
+	}
+}
+
+// handleCancelled aborts the request named by notifications/cancelled; unknown or finished requests are ignored
+func (s *Server) handleCancelled(req JSONRPCRequest) {
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+		Reason    string          `json:"reason"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params.RequestID) == 0 {
+		return
+	}
+	var id interface{}
+	if err := json.Unmarshal(params.RequestID, &id); err != nil {
+		return
+	}
+	key, _ := json.Marshal(id)
+
+	s.inflightMu.Lock()
+	cancel, ok := s.inflight[string(key)]
+	s.inflightMu.Unlock()
+	if ok {
+		cancel()
 	}
 }
 
-func (s *Server) send(resp JSONRPCResponse) {
-	bytes, err := json.Marshal(resp)
+// Notify sends a server-initiated notification to every connected client
+func (s *Server) Notify(method string, params interface{}) {
+	data, ok := notification(method, params)
+	if !ok {
+		return
+	}
+
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	for _, listener := range s.listeners {
+		listener(data)
+	}
+}
+
+// notification encodes a JSON-RPC notification
+func notification(method string, params interface{}) ([]byte, bool) {
+	data, err := json.Marshal(JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		// Not through the logger: its messages are notifications themselves
+		fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC notification: %v\n", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// startSession registers a client session for notifications addressed to particular sessions
+func (s *Server) startSession(sess *session) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	s.sessions[sess] = true
+}
+
+// endSession forgets a closed session
+func (s *Server) endSession(sess *session) {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	delete(s.sessions, sess)
+}
+
+// connectedSessions returns the registered sessions
+func (s *Server) connectedSessions() []*session {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// subscribe registers a transport for notifications and returns its unsubscribe function
+func (s *Server) subscribe(listener func([]byte)) func() {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	if s.listeners == nil {
+		s.listeners = make(map[int]func([]byte))
+	}
+	id := s.nextListener
+	s.nextListener++
+	s.listeners[id] = listener
+	return func() {
+		s.listenersMu.Lock()
+		defer s.listenersMu.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// lineWriter writes newline-delimited JSON; responses and notifications may come from different goroutines
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lineWriter) writeLine(data []byte) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	_, _ = lw.w.Write(append(data, '\n'))
+}
+
+func (lw *lineWriter) writeResponse(resp *JSONRPCResponse) {
+	data, err := json.Marshal(resp)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: failed to marshal JSON-RPC response: %v\n", err)
 		return
 	}
-	fmt.Printf("%s\n", string(bytes))
+	lw.writeLine(data)
 }
 
-func (s *Server) sendResult(id interface{}, result interface{}) {
-	s.send(JSONRPCResponse{
+func resultResponse(id interface{}, result interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 		Result:  result,
-	})
+	}
 }
 
-func (s *Server) sendError(id interface{}, code int, message string) {
-	s.send(JSONRPCResponse{
+func errorResponse(id interface{}, code int, message string) *JSONRPCResponse {
+	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 		Error:   &RPCError{Code: code, Message: message},
-	})
+	}
 }
 
-func (s *Server) handleInitialize(req JSONRPCRequest) {
-	s.sendResult(req.ID, map[string]interface{}{
-		"protocolVersion": "2024-11-05",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
+func (s *Server) handleInitialize(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ClientInfo      map[string]interface{}     `json:"clientInfo"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return errorResponse(req.ID, -32602, "Invalid params")
+		}
+	}
+
+	version, err := negotiateProtocol(params.ProtocolVersion)
+	if err != nil {
+		resp := errorResponse(req.ID, -32602, err.Error())
+		resp.Error.Data = map[string]interface{}{
+			"supported": supportedProtocolVersions,
+			"requested": params.ProtocolVersion,
+		}
+		return resp
+	}
+	if sess := sessionFrom(ctx); sess != nil {
+		sess.initialize(version, params.Capabilities, params.ClientInfo)
+	}
+
+	return resultResponse(req.ID, map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    s.capabilities(),
 		"serverInfo": map[string]string{
 			"name":    "quint-code",
-			"version": "4.0.0",
+			"version": s.version,
 		},
 	})
 }
 
-func (s *Server) handleToolsList(req JSONRPCRequest) {
-	tools := []Tool{
-		{
-			Name:        "quint_status",
-			Description: "Get current FPF phase and context.",
-			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			Name:        "quint_init",
-			Description: "Initialize FPF project structure.",
-			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			Name:        "quint_record_context",
-			Description: "Record the Bounded Context (A.1.1).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"vocabulary": map[string]string{"type": "string", "description": "Key terms"},
-					"invariants": map[string]string{"type": "string", "description": "System rules"},
-				},
-				"required": []string{"vocabulary", "invariants"},
-			},
-		},
-		{
-			Name:        "quint_propose",
-			Description: "Propose a new hypothesis (L0). IMPORTANT: Consider depends_on for dependencies and decision_context for grouping alternatives.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"title":     map[string]string{"type": "string", "description": "Title"},
-					"content":   map[string]string{"type": "string", "description": "Description"},
-					"scope":     map[string]string{"type": "string", "description": "Scope (G) - where this hypothesis applies"},
-					"kind":      map[string]interface{}{"type": "string", "enum": []interface{}{"system", "episteme"}, "description": "system=code/architecture, episteme=process/methodology"},
-					"rationale": map[string]string{"type": "string", "description": "JSON: {anomaly, approach, alternatives_rejected}"},
-					"decision_context": map[string]string{
-						"type":        "string",
-						"description": "Parent decision ID to GROUP competing alternatives. Does NOT affect R_eff. Use when multiple hypotheses solve the same problem. Example: 'caching-decision' groups 'redis-caching' and 'cdn-edge'. Creates MemberOf relation.",
-					},
-					"depends_on": map[string]interface{}{
-						"type":        "array",
-						"items":       map[string]string{"type": "string"},
-						"description": "IDs of holons this hypothesis REQUIRES to work. CRITICAL: Affects R_eff via WLNK - if dependency has low R, this inherits that ceiling. Use when: (1) builds on another hypothesis, (2) needs another to function, (3) dependency failure invalidates this. Leave empty for independent hypotheses. Creates ComponentOf/ConstituentOf.",
-					},
-					"dependency_cl": map[string]interface{}{
-						"type":        "integer",
-						"minimum":     1,
-						"maximum":     3,
-						"default":     3,
-						"description": "Congruence level for dependencies. CL3=same context (no penalty), CL2=similar (10% penalty), CL1=different (30% penalty).",
-					},
-				},
-				"required": []string{"title", "content", "scope", "kind", "rationale"},
-			},
-		},
-		{
-			Name:        "quint_verify",
-			Description: "Record verification results (L0 -> L1).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"hypothesis_id": map[string]string{"type": "string"},
-					"checks_json":   map[string]string{"type": "string", "description": "JSON of checks"},
-					"verdict":       map[string]interface{}{"type": "string", "enum": []interface{}{"PASS", "FAIL", "REFINE"}},
-				},
-				"required": []string{"hypothesis_id", "checks_json", "verdict"},
-			},
-		},
-		{
-			Name:        "quint_test",
-			Description: "Record validation results (L1 -> L2).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"hypothesis_id": map[string]string{"type": "string"},
-					"test_type":     map[string]string{"type": "string", "description": "internal or research"},
-					"result":        map[string]string{"type": "string", "description": "Test output/findings"},
-					"verdict":       map[string]interface{}{"type": "string", "enum": []interface{}{"PASS", "FAIL", "REFINE"}},
-				},
-				"required": []string{"hypothesis_id", "test_type", "result", "verdict"},
-			},
-		},
-		{
-			Name:        "quint_audit",
-			Description: "Record audit/trust score (R_eff).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"hypothesis_id": map[string]string{"type": "string"},
-					"risks":         map[string]string{"type": "string", "description": "Risk analysis"},
-				},
-				"required": []string{"hypothesis_id", "risks"},
-			},
-		},
-		{
-			Name:        "quint_decide",
-			Description: "Finalize decision (DRR).",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"title":     map[string]string{"type": "string"},
-					"winner_id": map[string]string{"type": "string"},
-					"rejected_ids": map[string]interface{}{
-						"type":        "array",
-						"items":       map[string]string{"type": "string"},
-						"description": "IDs of rejected L2 alternatives",
-					},
-					"context":         map[string]string{"type": "string"},
-					"decision":        map[string]string{"type": "string"},
-					"rationale":       map[string]string{"type": "string"},
-					"consequences":    map[string]string{"type": "string"},
-					"characteristics": map[string]string{"type": "string"},
-				},
-				"required": []string{"title", "winner_id", "context", "decision", "rationale", "consequences"},
-			},
-		},
-		{
-			Name:        "quint_actualize",
-			Description: "Reconcile the project's FPF state with recent repository changes.",
-			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			Name:        "quint_audit_tree",
-			Description: "Visualize the assurance tree for a holon, showing R scores, dependencies, and CL penalties.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"holon_id": map[string]string{"type": "string", "description": "ID of the holon to audit"},
-				},
-				"required": []string{"holon_id"},
-			},
-		},
-		{
-			Name:        "quint_calculate_r",
-			Description: "Calculate the effective reliability (R_eff) for a holon with detailed breakdown.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"holon_id": map[string]string{"type": "string", "description": "ID of the holon"},
-				},
-				"required": []string{"holon_id"},
-			},
-		},
-		{
-			Name:        "quint_check_decay",
-			Description: "Check evidence freshness and manage stale decisions. Without parameters: shows freshness report. With deprecate: downgrades hypothesis. With waive: records temporary risk acceptance.",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"deprecate": map[string]string{
-						"type":        "string",
-						"description": "Hypothesis ID to deprecate (L2→L1 or L1→L0)",
-					},
-					"waive_id": map[string]string{
-						"type":        "string",
-						"description": "Evidence ID to waive",
-					},
-					"waive_until": map[string]string{
-						"type":        "string",
-						"description": "ISO date until which waiver is valid (required with waive_id)",
-					},
-					"waive_rationale": map[string]string{
-						"type":        "string",
-						"description": "Reason for accepting stale evidence (required with waive_id)",
-					},
-				},
-			},
-		},
+// capabilities lists exactly what the server implements: tools and resources with change
+// notifications and resource subscriptions, logging, and prompts when templates are loaded
+func (s *Server) capabilities() map[string]interface{} {
+	capabilities := map[string]interface{}{
+		"tools":     map[string]interface{}{"listChanged": true},
+		"resources": map[string]interface{}{"subscribe": true, "listChanged": true},
+		"logging":   map[string]interface{}{},
+	}
+	if s.prompts != nil {
+		capabilities["prompts"] = map[string]interface{}{}
+	}
+	return capabilities
+}
+
+// handleSetLevel sets the minimum level of the notifications/message the client receives
+func (s *Server) handleSetLevel(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return errorResponse(req.ID, -32602, "Invalid params: level is required")
+	}
+	level, err := ParseLogLevel(params.Level)
+	if err != nil {
+		return errorResponse(req.ID, -32602, "Invalid params: "+err.Error())
+	}
+	sess := sessionFrom(ctx)
+	if sess == nil {
+		return errorResponse(req.ID, -32603, "logging/setLevel needs a client session")
 	}
+	sess.setLogLevel(level)
+	s.startSession(sess)
+	return resultResponse(req.ID, map[string]interface{}{})
+}
 
-	s.sendResult(req.ID, map[string]interface{}{
+// logMessage sends a log message as notifications/message. The session whose request caused it
+// receives it at or above its level; other sessions only if they chose a level with
+// logging/setLevel. Messages no request caused, such as a project that fails to open, go to
+// every session at or above its level.
+func (s *Server) logMessage(ctx context.Context, level LogLevel, message string) {
+	data, ok := notification("notifications/message", map[string]interface{}{
+		"level":  level.String(),
+		"logger": "quint-code",
+		"data":   message,
+	})
+	if !ok {
+		return
+	}
+	origin := sessionFrom(ctx)
+	if origin != nil && origin.wantsLog(level, true) {
+		origin.deliver(data)
+	}
+	for _, sess := range s.connectedSessions() {
+		if sess != origin && sess.wantsLog(level, origin == nil) {
+			sess.deliver(data)
+		}
+	}
+}
+
+func (s *Server) handleToolsList(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	structured := protocolAtLeast(ctx, structuredOutputSince)
+	annotated := protocolAtLeast(ctx, annotationsSince)
+	tools := make([]Tool, 0, len(toolRegistry))
+	for _, tool := range toolRegistry {
+		if s.readOnly && !tool.hints.readOnly {
+			continue
+		}
+		entry := Tool{
+			Name:        tool.name,
+			Description: tool.description,
+			InputSchema: tool.input,
+		}
+		if structured {
+			entry.OutputSchema = tool.output
+		}
+		if annotated {
+			entry.Annotations = tool.annotations()
+		}
+		tools = append(tools, entry)
+	}
+
+	return resultResponse(req.ID, map[string]interface{}{
 		"tools": tools,
 	})
 }
 
-func (s *Server) handleToolsCall(req JSONRPCRequest) {
+func (s *Server) handleToolsCall(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		s.sendError(req.ID, -32700, "Invalid params")
-		return
+		return errorResponse(req.ID, -32602, "Invalid params")
 	}
 
-	arg := func(k string) string {
-		if v, ok := params.Arguments[k].(string); ok {
-			return v
-		}
-		return ""
-	}
-
-	args := make(map[string]string)
-	for k, v := range params.Arguments {
-		if s, ok := v.(string); ok {
-			args[k] = s
-		}
+	tool, ok := lookupTool(params.Name)
+	if !ok {
+		return errorResponse(req.ID, -32602, fmt.Sprintf("Unknown tool: %s", params.Name))
 	}
 
-	if precondErr := s.tools.CheckPreconditions(params.Name, args); precondErr != nil {
-		s.tools.AuditLog(params.Name, "precondition_failed", "agent", "", "BLOCKED", args, precondErr.Error())
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: precondErr.Error()}},
+	if s.readOnly && tool.mutates(params.Arguments) {
+		return resultResponse(req.ID, CallToolResult{
+			Content: []ContentItem{{Type: "text", Text: fmt.Sprintf("%s would change the knowledge base, but this server is read-only", tool.name)}},
 			IsError: true,
 		})
-		return
 	}
 
-	var output string
-	var err error
+	project, _ := params.Arguments["project"].(string)
+	tools, err := s.projectFor(ctx, project)
+	if err != nil {
+		return errorResponse(req.ID, -32602, err.Error())
+	}
 
-	switch params.Name {
-	case "quint_status":
-		st := s.tools.FSM.State.Phase
-		output = string(st)
+	// A call that can change the project's Tools or FSM runs alone on that project; read-only
+	// calls and calls on other projects run side by side
+	if tool.mutates(params.Arguments) {
+		tools.callMu.Lock()
+		defer tools.callMu.Unlock()
+	} else {
+		tools.callMu.RLock()
+		defer tools.callMu.RUnlock()
+	}
+	if ctx.Err() != nil {
+		return nil // Cancelled while waiting for a running call
+	}
 
-	case "quint_init":
-		res := s.tools.InitProject()
-		if res != nil {
-			err = res
-		} else {
-			s.tools.FSM.State.Phase = PhaseAbduction
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
+	// Progress goes only to the client that asked for it, which knows the token
+	if sess, token := sessionFrom(ctx), params.Meta.ProgressToken; sess != nil && token != nil {
+		withMessage := protocolAtLeast(ctx, progressMessageSince)
+		ctx = WithProgress(ctx, func(progress, total float64, message string) {
+			if !withMessage {
+				message = ""
 			}
-			output = "Initialized. Phase: ABDUCTION"
-		}
-
-	case "quint_actualize":
-		output, err = s.tools.Actualize()
-
-	case "quint_record_context":
-		output, err = s.tools.RecordContext(arg("vocabulary"), arg("invariants"))
-
-	case "quint_propose":
-		s.tools.FSM.State.Phase = PhaseAbduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
-		}
-		decisionContext := arg("decision_context")
-		var dependsOn []string
-		if deps, ok := params.Arguments["depends_on"].([]interface{}); ok {
-			for _, d := range deps {
-				if s, ok := d.(string); ok {
-					dependsOn = append(dependsOn, s)
-				}
+			if data, ok := notification("notifications/progress", progressParams{ProgressToken: token, Progress: progress, Total: total, Message: message}); ok {
+				sess.deliver(data)
 			}
-		}
-		dependencyCL := 3
-		if cl, ok := params.Arguments["dependency_cl"].(float64); ok {
-			dependencyCL = int(cl)
-		}
-		output, err = s.tools.ProposeHypothesis(arg("title"), arg("content"), arg("scope"), arg("kind"), arg("rationale"), decisionContext, dependsOn, dependencyCL)
-
-	case "quint_verify":
-		s.tools.FSM.State.Phase = PhaseDeduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
-		}
-		output, err = s.tools.VerifyHypothesis(arg("hypothesis_id"), arg("checks_json"), arg("verdict"))
+		})
+	}
 
-	case "quint_test":
-		s.tools.FSM.State.Phase = PhaseInduction
-		if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
-		}
+	result, err := tools.CallTool(ctx, tool.name, params.Arguments)
+	var invalid *ErrInvalidArguments
+	if errors.As(err, &invalid) {
+		resp := errorResponse(req.ID, -32602, invalid.Error())
+		resp.Error.Data = map[string]interface{}{"tool": tool.name, "fields": invalid.Fields}
+		return resp
+	}
+	if err != nil {
+		// Failed preconditions and tool errors are results the agent can act on
+		return resultResponse(req.ID, CallToolResult{
+			Content: []ContentItem{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		})
+	}
+	callResult := CallToolResult{Content: []ContentItem{{Type: "text", Text: result.Markdown()}}}
+	if protocolAtLeast(ctx, structuredOutputSince) {
+		callResult.StructuredContent = result
+	}
+	return resultResponse(req.ID, callResult)
+}
 
-		assLevel := "L2"
-		if arg("verdict") != "PASS" {
-			assLevel = "L1"
+// handleResourcesList lists the resources of the session's default project, then those of every
+// other project it can select, with URIs that name the project
+func (s *Server) handleResourcesList(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	tools, err := s.projectFor(ctx, "")
+	if err != nil {
+		return errorResponse(req.ID, -32603, err.Error())
+	}
+	resources, err := tools.ListResources(ctx)
+	if err != nil {
+		return errorResponse(req.ID, -32603, err.Error())
+	}
+	for _, root := range s.otherProjects(ctx, tools.RootDir) {
+		other, err := s.openProject(root)
+		if err != nil {
+			return errorResponse(req.ID, -32603, err.Error())
 		}
-
-		output, err = s.tools.ManageEvidence(PhaseInduction, "add", arg("hypothesis_id"), arg("test_type"), arg("result"), arg("verdict"), assLevel, "test-runner", "")
-
-	case "quint_audit":
-		output, err = s.tools.AuditEvidence(arg("hypothesis_id"), arg("risks"))
-
-	case "quint_decide":
-		s.tools.FSM.State.Phase = PhaseDecision
-		var rejectedIDs []string
-		if rids, ok := params.Arguments["rejected_ids"].([]interface{}); ok {
-			for _, r := range rids {
-				if s, ok := r.(string); ok {
-					rejectedIDs = append(rejectedIDs, s)
-				}
-			}
+		list, err := other.ListResources(ctx)
+		if err != nil {
+			return errorResponse(req.ID, -32603, fmt.Sprintf("project %s: %v", root, err))
 		}
-		output, err = s.tools.FinalizeDecision(arg("title"), arg("winner_id"), rejectedIDs, arg("context"), arg("decision"), arg("rationale"), arg("consequences"), arg("characteristics"))
-		if err == nil {
-			s.tools.FSM.State.Phase = PhaseIdle
-			if saveErr := s.tools.FSM.SaveState("default"); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", saveErr)
-			}
+		for _, r := range list {
+			r.URI = projectResourceURI(r.URI, root)
+			r.Description = fmt.Sprintf("%s (project %s)", r.Description, filepath.Base(root))
+			resources = append(resources, r)
 		}
+	}
+	if resources == nil {
+		resources = []Resource{}
+	}
+	return resultResponse(req.ID, map[string]interface{}{
+		"resources": resources,
+	})
+}
+
+func (s *Server) handleResourceTemplatesList(req JSONRPCRequest) *JSONRPCResponse {
+	return resultResponse(req.ID, map[string]interface{}{
+		"resourceTemplates": s.tools.ListResourceTemplates(),
+	})
+}
 
-	case "quint_audit_tree":
-		output, err = s.tools.VisualizeAudit(arg("holon_id"))
+func (s *Server) handleResourcesRead(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		return errorResponse(req.ID, -32602, "Invalid params: uri is required")
+	}
 
-	case "quint_calculate_r":
-		output, err = s.tools.CalculateR(arg("holon_id"))
+	uri, project := splitResourceURI(params.URI)
+	tools, err := s.projectFor(ctx, project)
+	if err != nil {
+		return errorResponse(req.ID, -32002, fmt.Sprintf("resource not found: %s: %v", params.URI, err))
+	}
+	contents, err := tools.ReadResource(ctx, uri)
+	var notFound *ErrResourceNotFound
+	if errors.As(err, &notFound) {
+		return errorResponse(req.ID, -32002, fmt.Sprintf("resource not found: %s", params.URI))
+	}
+	if err != nil {
+		return errorResponse(req.ID, -32603, err.Error())
+	}
+	contents.URI = params.URI
+	return resultResponse(req.ID, map[string]interface{}{
+		"contents": []ResourceContents{contents},
+	})
+}
 
-	case "quint_check_decay":
-		output, err = s.tools.CheckDecay(arg("deprecate"), arg("waive_id"), arg("waive_until"), arg("waive_rationale"))
+func (s *Server) handlePromptsList(req JSONRPCRequest) *JSONRPCResponse {
+	if s.prompts == nil {
+		return errorResponse(req.ID, -32601, "Method not found")
+	}
+	prompts, err := s.prompts.List()
+	if err != nil {
+		return errorResponse(req.ID, -32603, err.Error())
+	}
+	return resultResponse(req.ID, map[string]interface{}{
+		"prompts": prompts,
+	})
+}
 
-	default:
-		err = fmt.Errorf("unknown tool: %s", params.Name)
+func (s *Server) handlePromptsGet(req JSONRPCRequest) *JSONRPCResponse {
+	if s.prompts == nil {
+		return errorResponse(req.ID, -32601, "Method not found")
+	}
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.Name == "" {
+		return errorResponse(req.ID, -32602, "Invalid params: name is required")
 	}
 
+	description, messages, err := s.prompts.Get(params.Name, params.Arguments)
 	if err != nil {
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: err.Error()}},
-			IsError: true,
-		})
-	} else {
-		s.sendResult(req.ID, CallToolResult{
-			Content: []ContentItem{{Type: "text", Text: output}},
-		})
+		return errorResponse(req.ID, -32602, err.Error())
 	}
+	return resultResponse(req.ID, map[string]interface{}{
+		"description": description,
+		"messages":    messages,
+	})
 }
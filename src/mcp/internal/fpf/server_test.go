@@ -0,0 +1,510 @@
+package fpf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer collects Serve output while workers are still writing
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) responses(t *testing.T) map[string]JSONRPCResponse {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	responses := make(map[string]JSONRPCResponse)
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp JSONRPCResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid output line %q: %v", line, err)
+		}
+		if resp.ID != nil {
+			id, _ := json.Marshal(resp.ID)
+			responses[string(id)] = resp
+		}
+	}
+	return responses
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServe_LongLines(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	padding := strings.Repeat("x", 256*1024)
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"padding":"` + padding + `"}}` + "\n" +
+		"not json\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` // No trailing newline
+
+	var out syncBuffer
+	if err := server.Serve(t.Context(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	responses := out.responses(t)
+	for _, id := range []string{"1", "2"} {
+		if resp, ok := responses[id]; !ok || resp.Error != nil {
+			t.Errorf("Expected a tools/list result for request %s, got %+v", id, resp)
+		}
+	}
+	if !strings.Contains(out.buf.String(), `"code":-32700`) {
+		t.Errorf("Expected a parse error for the invalid line, got %s", out.buf.String())
+	}
+}
+
+func TestServe_CancelledRequest(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	in, w := io.Pipe()
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(t.Context(), in, &out) }()
+
+	// Hold the tool lock so the call stays pending until it is cancelled
+	tools.callMu.Lock()
+	io.WriteString(w, `{"jsonrpc":"2.0","id":"slow","method":"tools/call","params":{"name":"quint_status","arguments":{}}}`+"\n")
+	waitFor(t, "request to be in flight", func() bool {
+		server.inflightMu.Lock()
+		defer server.inflightMu.Unlock()
+		return server.inflight[`"slow"`] != nil
+	})
+
+	io.WriteString(w, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"slow","reason":"user"}}`+"\n")
+	io.WriteString(w, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n")
+	waitFor(t, "tools/list response while the call is pending", func() bool {
+		_, ok := out.responses(t)["2"]
+		return ok
+	})
+	tools.callMu.Unlock()
+
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if resp, ok := out.responses(t)[`"slow"`]; ok {
+		t.Errorf("Expected no response for the cancelled request, got %+v", resp)
+	}
+}
+
+func TestServe_CancelQueuedRequest(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	in, w := io.Pipe()
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(t.Context(), in, &out) }()
+
+	// Every worker waits behind the held lock, and one more call queues behind them
+	tools.callMu.Lock()
+	queued := stdioWorkers + 1
+	for i := 1; i <= queued; i++ {
+		io.WriteString(w, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"quint_status","arguments":{}}}`, i)+"\n")
+	}
+	io.WriteString(w, fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":%d}}`, queued)+"\n")
+	waitFor(t, "the queued request to be cancelled", func() bool {
+		server.inflightMu.Lock()
+		defer server.inflightMu.Unlock()
+		_, ok := server.inflight[fmt.Sprint(queued)]
+		return !ok && len(server.inflight) == stdioWorkers
+	})
+	tools.callMu.Unlock()
+
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	responses := out.responses(t)
+	if len(responses) != stdioWorkers {
+		t.Errorf("Expected %d responses, got %d", stdioWorkers, len(responses))
+	}
+	if resp, ok := responses[fmt.Sprint(queued)]; ok {
+		t.Errorf("Expected no response for the cancelled request, got %+v", resp)
+	}
+}
+
+func TestToolsCall_ReadOnlyCallsRunConcurrently(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseAbduction
+
+	// A read-only call in progress elsewhere
+	tools.callMu.RLock()
+
+	resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"quint_status","arguments":{}}`)})
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("Expected quint_status to run beside another read-only call, got %+v", resp)
+	}
+
+	proposed := make(chan *JSONRPCResponse, 1)
+	go func() {
+		proposed <- server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"quint_propose","arguments":{` + proposeCacheArgs + `}}`)})
+	}()
+	select {
+	case resp := <-proposed:
+		t.Errorf("Expected quint_propose to wait for the read-only call, got %+v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+	tools.callMu.RUnlock()
+	if resp := <-proposed; resp == nil || resp.Error != nil {
+		t.Errorf("Expected quint_propose to run once the read-only call finished, got %+v", resp)
+	}
+}
+
+func TestToolsCall_ReportsProgress(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseInduction
+	ctx := t.Context()
+
+	for _, id := range []string{"a", "b"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L2", id, "Content", "default", "global", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	others := captureNotifications(t, server)
+	record, notifications := recordNotifications(t)
+	ctx = withSession(ctx, &session{send: record})
+
+	resp := server.Handle(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"quint_check_decay","arguments":{},"_meta":{"progressToken":"decay-1"}}`),
+	})
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("tools/call failed: %+v", resp)
+	}
+
+	var updates []progressParams
+	for _, n := range notifications() {
+		if n.Method != "notifications/progress" {
+			continue
+		}
+		data, _ := json.Marshal(n.Params)
+		var p progressParams
+		if err := json.Unmarshal(data, &p); err != nil {
+			t.Fatal(err)
+		}
+		updates = append(updates, p)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("Expected 2 progress notifications, got %+v", updates)
+	}
+	last := updates[1]
+	if last.ProgressToken != "decay-1" || last.Progress != 2 || last.Total != 2 {
+		t.Errorf("Unexpected final progress: %+v", last)
+	}
+	if got := others(); notificationFor(got, "notifications/progress", "") {
+		t.Errorf("Expected progress only on the requesting session, other clients got %+v", got)
+	}
+}
+
+func TestInitialize_NegotiatesProtocol(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+	server.SetVersion("1.2.3")
+
+	tests := []struct {
+		requested string
+		want      string // Empty: rejected
+	}{
+		{"2025-06-18", "2025-06-18"},
+		{"2025-03-26", "2025-03-26"},
+		{"2024-11-05", "2024-11-05"},
+		{"2099-01-01", "2025-06-18"},
+		{"2025-05-01", "2025-03-26"},
+		{"", "2025-06-18"},
+		{"2024-10-07", ""},
+		{"latest", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			params, _ := json.Marshal(map[string]interface{}{"protocolVersion": tt.requested, "capabilities": map[string]interface{}{}})
+			resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize", Params: params})
+
+			if tt.want == "" {
+				if resp.Error == nil || resp.Error.Code != -32602 {
+					t.Fatalf("Expected -32602, got %+v", resp)
+				}
+				data := resp.Error.Data.(map[string]interface{})
+				if data["requested"] != tt.requested || len(data["supported"].([]string)) != len(supportedProtocolVersions) {
+					t.Errorf("Expected requested and supported versions in error data, got %+v", data)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("initialize failed: %+v", resp.Error)
+			}
+			result := resp.Result.(map[string]interface{})
+			if result["protocolVersion"] != tt.want {
+				t.Errorf("Expected %s, got %v", tt.want, result["protocolVersion"])
+			}
+			if info := result["serverInfo"].(map[string]string); info["version"] != "1.2.3" {
+				t.Errorf("Expected build version 1.2.3, got %+v", info)
+			}
+			capabilities := result["capabilities"].(map[string]interface{})
+			if _, ok := capabilities["prompts"]; ok {
+				t.Errorf("Expected no prompts capability without templates, got %+v", capabilities)
+			}
+		})
+	}
+}
+
+func TestServe_OlderProtocolOmitsStructuredOutput(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	server := NewServer(tools)
+
+	in, w := io.Pipe()
+	var out syncBuffer
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(t.Context(), in, &out) }()
+
+	io.WriteString(w, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`+"\n")
+	waitFor(t, "initialize response", func() bool {
+		_, ok := out.responses(t)["1"]
+		return ok
+	})
+	io.WriteString(w, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n")
+	w.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	output := out.buf.String()
+	if !strings.Contains(output, `"name":"quint_status"`) {
+		t.Fatalf("Expected a tools/list response, got %s", output)
+	}
+	if strings.Contains(output, `"outputSchema"`) {
+		t.Errorf("Expected no outputSchema for a 2025-03-26 session")
+	}
+}
+
+func TestPingAndLogging(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	var stderr bytes.Buffer
+	tools.Log = NewLogger(&stderr)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseAbduction
+
+	// a chooses warning, b keeps the default, c opts in to errors of every session
+	sessions := map[string]*session{}
+	drains := map[string]func() []JSONRPCNotification{}
+	for _, name := range []string{"a", "b", "c"} {
+		record, drain := recordNotifications(t)
+		sessions[name], drains[name] = &session{send: record}, drain
+		server.startSession(sessions[name])
+	}
+	setLevel := func(name, level string) *JSONRPCResponse {
+		return server.Handle(withSession(t.Context(), sessions[name]), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "logging/setLevel", Params: json.RawMessage(`{"level":"` + level + `"}`)})
+	}
+
+	if resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"}); resp.Error != nil {
+		t.Fatalf("ping failed: %+v", resp.Error)
+	}
+	if resp := setLevel("a", "loud"); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("Expected -32602 for an unknown level, got %+v", resp)
+	}
+	for name, level := range map[string]string{"a": "warning", "c": "error"} {
+		if resp := setLevel(name, level); resp.Error != nil {
+			t.Fatalf("logging/setLevel failed: %+v", resp.Error)
+		}
+	}
+
+	ctxA := withSession(t.Context(), sessions["a"])
+	tools.Log.Infof(ctxA, "below the client level")
+	if _, err := tools.ProposeHypothesis(ctxA, "Cache", "c", "global", "system", "{}", "", []string{"missing"}, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	tools.Log.Errorf(withSession(t.Context(), sessions["b"]), "failure in b")
+
+	messages := func(name string) []string {
+		var got []string
+		for _, n := range drains[name]() {
+			if n.Method == "notifications/message" {
+				params := n.Params.(map[string]interface{})
+				got = append(got, params["level"].(string)+": "+params["data"].(string))
+			}
+		}
+		return got
+	}
+	if got := messages("a"); len(got) != 2 || !strings.Contains(got[0], "warning: dependency 'missing' not found") || got[1] != "error: failure in b" {
+		t.Errorf("Expected a's warning and, having chosen a level, b's error, got %q", got)
+	}
+	if got := messages("b"); len(got) != 1 || got[0] != "error: failure in b" {
+		t.Errorf("Expected only b's own error, got %q", got)
+	}
+	if got := messages("c"); len(got) != 1 || got[0] != "error: failure in b" {
+		t.Errorf("Expected only errors at c's level, got %q", got)
+	}
+	if !strings.Contains(stderr.String(), "Warning: dependency 'missing' not found") || strings.Contains(stderr.String(), "below the client level") {
+		t.Errorf("Expected only the warning on stderr, got %q", stderr.String())
+	}
+}
+
+func TestToolsList_Annotations(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	resp := NewServer(tools).Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+
+	annotations := make(map[string]*ToolAnnotations)
+	for _, tool := range resp.Result.(map[string]interface{})["tools"].([]Tool) {
+		if tool.Annotations == nil {
+			t.Fatalf("%s: missing annotations", tool.Name)
+		}
+		annotations[tool.Name] = tool.Annotations
+	}
+	if a := annotations["quint_status"]; !a.ReadOnlyHint || !a.IdempotentHint || a.DestructiveHint || a.OpenWorldHint {
+		t.Errorf("Expected quint_status to be read-only and idempotent, got %+v", a)
+	}
+	if a := annotations["quint_check_decay"]; a.ReadOnlyHint || !a.DestructiveHint {
+		t.Errorf("Expected quint_check_decay to be destructive, got %+v", a)
+	}
+	if a := annotations["quint_propose"]; a.ReadOnlyHint || a.DestructiveHint || a.IdempotentHint {
+		t.Errorf("Expected quint_propose to be additive, got %+v", a)
+	}
+}
+
+func TestToolsCall_ReadOnlyServer(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	server.SetReadOnly(true)
+	fsm.State.Phase = PhaseAbduction
+
+	resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	var names []string
+	for _, tool := range resp.Result.(map[string]interface{})["tools"].([]Tool) {
+		names = append(names, tool.Name)
+	}
+	if slices.Contains(names, "quint_propose") || !slices.Contains(names, "quint_check_decay") || !slices.Contains(names, "quint_status") {
+		t.Errorf("Expected only tools that can run read-only, got %v", names)
+	}
+
+	call := func(id int, params string) CallToolResult {
+		t.Helper()
+		resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "tools/call", Params: json.RawMessage(params)})
+		if resp.Error != nil {
+			t.Fatalf("tools/call failed: %+v", resp.Error)
+		}
+		return resp.Result.(CallToolResult)
+	}
+
+	if result := call(2, `{"name":"quint_propose","arguments":{`+proposeCacheArgs+`}}`); !result.IsError || !strings.Contains(result.Content[0].Text, "read-only") {
+		t.Errorf("Expected quint_propose to be refused, got %+v", result)
+	}
+	if _, err := tools.DB.GetHolon(t.Context(), "cache"); err == nil {
+		t.Error("Expected no holon from a refused call")
+	}
+	if result := call(3, `{"name":"quint_check_decay","arguments":{"deprecate":"cache"}}`); !result.IsError {
+		t.Errorf("Expected deprecation to be refused, got %+v", result)
+	}
+	if result := call(4, `{"name":"quint_check_decay","arguments":{}}`); result.IsError {
+		t.Errorf("Expected the decay report to run, got %+v", result)
+	}
+}
+
+func TestToolsCall_ReadOnlyToolsSkipBookkeeping(t *testing.T) {
+	for _, readOnly := range []bool{false, true} {
+		t.Run(fmt.Sprintf("read-only server %v", readOnly), func(t *testing.T) {
+			tools, _, _ := setupTools(t)
+			server := NewServer(tools)
+			server.SetReadOnly(readOnly)
+			if err := tools.DB.CreateHolon(t.Context(), "cache", "hypothesis", "system", "L1", "Cache", "c", "default", "global", ""); err != nil {
+				t.Fatal(err)
+			}
+			raw := tools.DB.GetRawDB()
+			if _, err := raw.Exec(`UPDATE holons SET cached_r_score = 0.42 WHERE id = 'cache'`); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, params := range []string{
+				`{"name":"quint_calculate_r","arguments":{"holon_id":"cache"}}`,
+				`{"name":"quint_audit_tree","arguments":{"holon_id":"cache"}}`,
+				`{"name":"quint_what_if","arguments":{"holon_id":"cache"}}`,
+				`{"name":"quint_check_scope","arguments":{"holon_id":"cache","slice":"env=prod"}}`,
+				`{"name":"quint_check_graph","arguments":{}}`,
+			} {
+				resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: i + 1, Method: "tools/call", Params: json.RawMessage(params)})
+				if resp.Error != nil || resp.Result.(CallToolResult).IsError {
+					t.Fatalf("tools/call %s failed: %+v", params, resp)
+				}
+			}
+
+			var cached float64
+			var work, audit int
+			if err := raw.QueryRow(`SELECT cached_r_score FROM holons WHERE id = 'cache'`).Scan(&cached); err != nil {
+				t.Fatal(err)
+			}
+			if err := raw.QueryRow(`SELECT COUNT(*) FROM work_records`).Scan(&work); err != nil {
+				t.Fatal(err)
+			}
+			if err := raw.QueryRow(`SELECT COUNT(*) FROM audit_log`).Scan(&audit); err != nil {
+				t.Fatal(err)
+			}
+			if cached != 0.42 || work != 0 || audit != 0 {
+				t.Errorf("Expected no writes from read-only tools, got cached_r_score %v, %d work records and %d audit rows", cached, work, audit)
+			}
+		})
+	}
+}
+
+func TestToolsCall_ChangesRefreshScoreCache(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseAbduction
+	if err := tools.DB.CreateHolon(t.Context(), "stale", "hypothesis", "system", "L1", "Stale", "s", "default", "global", ""); err != nil {
+		t.Fatal(err)
+	}
+	raw := tools.DB.GetRawDB()
+	if _, err := raw.Exec(`UPDATE holons SET cached_r_score = 0.42 WHERE id = 'stale'`); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := server.Handle(t.Context(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"quint_propose","arguments":{` + proposeCacheArgs + `}}`)})
+	if resp.Error != nil || resp.Result.(CallToolResult).IsError {
+		t.Fatalf("quint_propose failed: %+v", resp)
+	}
+
+	var cached float64
+	if err := raw.QueryRow(`SELECT cached_r_score FROM holons WHERE id = 'stale'`).Scan(&cached); err != nil {
+		t.Fatal(err)
+	}
+	if cached != 0 {
+		t.Errorf("Expected a change to re-cache R_eff 0 for a holon without evidence, got %v", cached)
+	}
+}
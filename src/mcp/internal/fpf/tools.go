@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/m0n0x41d/quint-code/assurance"
@@ -22,25 +23,36 @@ import (
 var slugifyRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
 
 type Tools struct {
-	FSM     *FSM
-	RootDir string
-	DB      *db.Store
+	FSM      *FSM
+	RootDir  string
+	DB       *db.Store
+	Notifier Notifier // Told about knowledge-base changes; nil when nobody listens
+	Log      *Logger  // Warnings for stderr and, when served, for the client
+	ReadOnly bool     // Skip bookkeeping writes: cached R scores, work records, audit log
+
+	callMu sync.RWMutex // Held exclusively by server tool calls that can change the project
 }
 
 func NewTools(fsm *FSM, rootDir string, database *db.Store) *Tools {
+	log := NewLogger(os.Stderr)
 	if database == nil {
 		dbPath := filepath.Join(rootDir, ".quint", "quint.db")
 		var err error
 		database, err = db.NewStore(dbPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to open database in NewTools: %v\n", err)
+			log.Warnf(context.Background(), "failed to open database in NewTools: %v", err)
 		}
 	}
 
+	if fsm != nil && fsm.PolicyDir == "" {
+		fsm.PolicyDir = filepath.Join(rootDir, ".quint")
+	}
+
 	return &Tools{
 		FSM:     fsm,
 		RootDir: rootDir,
 		DB:      database,
+		Log:     log,
 	}
 }
 
@@ -48,8 +60,53 @@ func (t *Tools) GetFPFDir() string {
 	return filepath.Join(t.RootDir, ".quint")
 }
 
-func (t *Tools) AuditLog(toolName, operation, actor, targetID, result string, input interface{}, details string) {
-	if t.DB == nil {
+type readOnlyCallKey struct{}
+
+// withoutBookkeeping marks ctx as a read-only tool call: it writes no cached R scores, work records or audit log
+func withoutBookkeeping(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyCallKey{}, true)
+}
+
+// keepsBooks reports whether a call with ctx may write bookkeeping
+func (t *Tools) keepsBooks(ctx context.Context) bool {
+	return t.DB != nil && !t.ReadOnly && ctx.Value(readOnlyCallKey{}) == nil
+}
+
+// calculator returns an assurance calculator using the project policy (.quint/assurance.json),
+// loaded the same way as for the FSM Operation gate
+func (t *Tools) calculator(ctx context.Context) (*assurance.Calculator, error) {
+	policy, err := t.FSM.AssurancePolicy()
+	if err != nil {
+		return nil, err
+	}
+	calc := assurance.NewWithPolicy(t.DB.GetRawDB(), policy)
+	calc.ReadOnly = !t.keepsBooks(ctx)
+	return calc, nil
+}
+
+// refreshScores caches R_eff for every holon after a call that may have changed it and
+// tells subscribers about the holons whose score moved. It runs even for a cancelled request.
+func (t *Tools) refreshScores(ctx context.Context) {
+	if !t.keepsBooks(ctx) {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		t.Log.Warnf(ctx, "failed to update R cache: %v", err)
+		return
+	}
+	before, _ := t.cachedScores(ctx)
+	if _, err := calc.CalculateAll(ctx); err != nil {
+		t.Log.Warnf(ctx, "failed to update R cache: %v", err)
+		return
+	}
+	t.notifyScoreChanges(ctx, before)
+}
+
+// AuditLog records a tool operation; ctx only routes warnings, so the record is kept even for a cancelled request
+func (t *Tools) AuditLog(ctx context.Context, toolName, operation, actor, targetID, result string, input interface{}, details string) {
+	if !t.keepsBooks(ctx) {
 		return
 	}
 
@@ -63,9 +120,8 @@ func (t *Tools) AuditLog(toolName, operation, actor, targetID, result string, in
 	}
 
 	id := uuid.New().String()
-	ctx := context.Background()
-	if err := t.DB.InsertAuditLog(ctx, id, toolName, operation, actor, targetID, inputHash, result, details, "default"); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to insert audit log: %v\n", err)
+	if err := t.DB.InsertAuditLog(context.Background(), id, toolName, operation, actor, targetID, inputHash, result, details, "default"); err != nil {
+		t.Log.Warnf(ctx, "failed to insert audit log: %v", err)
 	}
 }
 
@@ -74,27 +130,29 @@ func (t *Tools) Slugify(title string) string {
 	return strings.Trim(slug, "-")
 }
 
-func (t *Tools) MoveHypothesis(hypothesisID, sourceLevel, destLevel string) (string, error) {
+func (t *Tools) MoveHypothesis(ctx context.Context, hypothesisID, sourceLevel, destLevel string) (string, error) {
 	srcPath := filepath.Join(t.GetFPFDir(), "knowledge", sourceLevel, hypothesisID+".md")
 	destPath := filepath.Join(t.GetFPFDir(), "knowledge", destLevel, hypothesisID+".md")
 
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
-		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, "not found")
+		t.AuditLog(ctx, "quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, "not found")
 		return "", fmt.Errorf("hypothesis %s not found in %s", hypothesisID, sourceLevel)
 	}
 
 	if err := os.Rename(srcPath, destPath); err != nil {
-		t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
+		t.AuditLog(ctx, "quint_move", "move_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"from": sourceLevel, "to": destLevel}, err.Error())
 		return "", fmt.Errorf("failed to move hypothesis from %s to %s: %v", sourceLevel, destLevel, err)
 	}
 
 	if t.DB != nil {
-		if err := t.DB.UpdateHolonLayer(context.Background(), hypothesisID, destLevel); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update holon layer in DB: %v\n", err)
+		if err := t.DB.UpdateHolonLayer(ctx, hypothesisID, destLevel); err != nil {
+			t.Log.Warnf(ctx, "failed to update holon layer in DB: %v", err)
 		}
 	}
 
-	t.AuditLog("quint_move", "move_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"from": sourceLevel, "to": destLevel}, "")
+	t.AuditLog(ctx, "quint_move", "move_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"from": sourceLevel, "to": destLevel}, "")
+	t.resourceUpdated(holonURI(hypothesisID))
+	t.resourceListChanged()
 	return destPath, nil
 }
 
@@ -124,7 +182,7 @@ func (t *Tools) InitProject() error {
 		dbPath := filepath.Join(t.GetFPFDir(), "quint.db")
 		database, err := db.NewStore(dbPath)
 		if err != nil {
-			fmt.Printf("Warning: Failed to init DB: %v\n", err)
+			t.Log.Warnf(context.Background(), "failed to init DB: %v", err)
 		} else {
 			t.DB = database
 		}
@@ -146,6 +204,7 @@ func (t *Tools) RecordContext(vocabulary, invariants string) (string, error) {
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return "", err
 	}
+	t.resourceUpdated(contextURI)
 	return path, nil
 }
 
@@ -228,8 +287,9 @@ func (t *Tools) GetAgentContext(role string) (string, error) {
 	return string(content), nil
 }
 
-func (t *Tools) RecordWork(methodName string, start time.Time) {
-	if t.DB == nil {
+// RecordWork records how long a method ran; like AuditLog it writes even for a cancelled request
+func (t *Tools) RecordWork(ctx context.Context, methodName string, start time.Time) {
+	if !t.keepsBooks(ctx) {
 		return
 	}
 	end := time.Now()
@@ -242,12 +302,16 @@ func (t *Tools) RecordWork(methodName string, start time.Time) {
 
 	ledger := fmt.Sprintf(`{"duration_ms": %d}`, end.Sub(start).Milliseconds())
 	if err := t.DB.RecordWork(context.Background(), id, methodName, performer, start, end, ledger); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to record work in DB: %v\n", err)
+		t.Log.Warnf(ctx, "failed to record work in DB: %v", err)
 	}
 }
 
-func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string, decisionContext string, dependsOn []string, dependencyCL int) (string, error) {
-	defer t.RecordWork("ProposeHypothesis", time.Now())
+func (t *Tools) ProposeHypothesis(ctx context.Context, title, content, scope, kind, rationale string, decisionContext string, dependsOn []string, dependencyCL int, formality int) (string, error) {
+	defer t.RecordWork(ctx, "ProposeHypothesis", time.Now())
+
+	if formality < assurance.MinFormality || formality > assurance.MaxFormality {
+		return "", fmt.Errorf("formality must be between F%d and F%d, got F%d", assurance.MinFormality, assurance.MaxFormality, formality)
+	}
 
 	slug := t.Slugify(title)
 	filename := fmt.Sprintf("%s.md", slug)
@@ -260,24 +324,26 @@ func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string,
 	}
 
 	if err := WriteWithHash(path, fields, body); err != nil {
-		t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
+		t.AuditLog(ctx, "quint_propose", "create_hypothesis", "agent", slug, "ERROR", map[string]string{"title": title, "kind": kind}, err.Error())
 		return "", err
 	}
 
 	if t.DB != nil {
-		if err := t.DB.CreateHolon(context.Background(), slug, "hypothesis", kind, "L0", title, body, "default", scope, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create holon in DB: %v\n", err)
+		if err := t.DB.CreateHolon(ctx, slug, "hypothesis", kind, "L0", title, body, "default", scope, ""); err != nil {
+			t.Log.Warnf(ctx, "failed to create holon in DB: %v", err)
+		} else if formality > assurance.MinFormality {
+			if err := t.DB.UpdateHolonFormality(ctx, slug, formality); err != nil {
+				t.Log.Warnf(ctx, "failed to set formality in DB: %v", err)
+			}
 		}
 	}
 
-	ctx := context.Background()
-
 	if decisionContext != "" && t.DB != nil {
 		if _, err := t.DB.GetHolon(ctx, decisionContext); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: decision_context '%s' not found, skipping MemberOf\n", decisionContext)
+			t.Log.Warnf(ctx, "decision_context '%s' not found, skipping MemberOf", decisionContext)
 		} else {
 			if err := t.createRelation(ctx, slug, "memberOf", decisionContext, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create MemberOf relation: %v\n", err)
+				t.Log.Warnf(ctx, "failed to create MemberOf relation: %v", err)
 			}
 		}
 	}
@@ -294,23 +360,24 @@ func (t *Tools) ProposeHypothesis(title, content, scope, kind, rationale string,
 
 		for _, depID := range dependsOn {
 			if _, err := t.DB.GetHolon(ctx, depID); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: dependency '%s' not found, skipping\n", depID)
+				t.Log.Warnf(ctx, "dependency '%s' not found, skipping", depID)
 				continue
 			}
 
 			if cyclic, _ := t.wouldCreateCycle(ctx, depID, slug); cyclic {
-				fmt.Fprintf(os.Stderr, "Warning: dependency on '%s' would create cycle, skipping\n", depID)
+				t.Log.Warnf(ctx, "dependency on '%s' would create cycle, skipping", depID)
 				continue
 			}
 
 			if err := t.createRelation(ctx, depID, relationType, slug, dependencyCL); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create %s relation to %s: %v\n",
+				t.Log.Warnf(ctx, "failed to create %s relation to %s: %v",
 					relationType, depID, err)
 			}
 		}
 	}
 
-	t.AuditLog("quint_propose", "create_hypothesis", "agent", slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope}, "")
+	t.AuditLog(ctx, "quint_propose", "create_hypothesis", "agent", slug, "SUCCESS", map[string]string{"title": title, "kind": kind, "scope": scope, "formality": fmt.Sprintf("F%d", formality)}, "")
+	t.resourceListChanged()
 
 	return path, nil
 }
@@ -324,7 +391,7 @@ func (t *Tools) createRelation(ctx context.Context, sourceID, relationType, targ
 		return err
 	}
 
-	t.AuditLog("quint_propose", "create_relation", "agent", sourceID, "SUCCESS",
+	t.AuditLog(ctx, "quint_propose", "create_relation", "agent", sourceID, "SUCCESS",
 		map[string]string{"relation": relationType, "target": targetID, "cl": fmt.Sprintf("%d", cl)}, "")
 
 	return nil
@@ -359,12 +426,18 @@ func (t *Tools) isReachable(ctx context.Context, from, to string, visited map[st
 	return false, nil
 }
 
-func (t *Tools) VerifyHypothesis(hypothesisID, checksJSON, verdict string) (string, error) {
-	defer t.RecordWork("VerifyHypothesis", time.Now())
+// VerifyHypothesis records the deduction verdict. A non-negative formality
+// replaces the holon's F level on PASS; pass -1 to keep the current level.
+func (t *Tools) VerifyHypothesis(ctx context.Context, hypothesisID, checksJSON, verdict string, formality int) (string, error) {
+	defer t.RecordWork(ctx, "VerifyHypothesis", time.Now())
+
+	if formality > assurance.MaxFormality {
+		return "", fmt.Errorf("formality must be between F%d and F%d, got F%d", assurance.MinFormality, assurance.MaxFormality, formality)
+	}
 
 	carrierRef := "internal-logic"
 	if t.DB != nil {
-		holon, err := t.DB.GetHolon(context.Background(), hypothesisID)
+		holon, err := t.DB.GetHolon(ctx, hypothesisID)
 		if err == nil && holon.Kind.Valid {
 			switch holon.Kind.String {
 			case "system":
@@ -377,48 +450,53 @@ func (t *Tools) VerifyHypothesis(hypothesisID, checksJSON, verdict string) (stri
 
 	switch strings.ToLower(verdict) {
 	case "pass":
-		_, err := t.MoveHypothesis(hypothesisID, "L0", "L1")
+		_, err := t.MoveHypothesis(ctx, hypothesisID, "L0", "L1")
 		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+			t.AuditLog(ctx, "quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
 			return "", err
 		}
 
+		if formality >= assurance.MinFormality && t.DB != nil {
+			if err := t.DB.UpdateHolonFormality(ctx, hypothesisID, formality); err != nil {
+				t.Log.Warnf(ctx, "failed to set formality for %s: %v", hypothesisID, err)
+			}
+		}
+
 		evidenceContent := fmt.Sprintf("Verification Checks:\n%s", checksJSON)
-		if _, err := t.ManageEvidence(PhaseDeduction, "add", hypothesisID, "verification", evidenceContent, "pass", "L1", carrierRef, ""); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to record verification evidence for %s: %v\n", hypothesisID, err)
+		if _, err := t.ManageEvidence(ctx, PhaseDeduction, "add", hypothesisID, "verification", evidenceContent, "pass", "L1", carrierRef, ""); err != nil {
+			t.Log.Warnf(ctx, "failed to record verification evidence for %s: %v", hypothesisID, err)
 		}
 
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "PASS", "result": "L1"}, "")
+		t.AuditLog(ctx, "quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "PASS", "result": "L1"}, "")
 		return fmt.Sprintf("Hypothesis %s (kind: %s) promoted to L1", hypothesisID, carrierRef), nil
 	case "fail":
-		_, err := t.MoveHypothesis(hypothesisID, "L0", "invalid")
+		_, err := t.MoveHypothesis(ctx, hypothesisID, "L0", "invalid")
 		if err != nil {
-			t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
+			t.AuditLog(ctx, "quint_verify", "verify_hypothesis", "agent", hypothesisID, "ERROR", map[string]string{"verdict": verdict}, err.Error())
 			return "", err
 		}
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "FAIL", "result": "invalid"}, "")
+		t.AuditLog(ctx, "quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "FAIL", "result": "invalid"}, "")
 		return fmt.Sprintf("Hypothesis %s moved to invalid", hypothesisID), nil
 	case "refine":
-		t.AuditLog("quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "REFINE", "result": "L0"}, "")
+		t.AuditLog(ctx, "quint_verify", "verify_hypothesis", "agent", hypothesisID, "SUCCESS", map[string]string{"verdict": "REFINE", "result": "L0"}, "")
 		return fmt.Sprintf("Hypothesis %s requires refinement (staying in L0)", hypothesisID), nil
 	default:
 		return "", fmt.Errorf("unknown verdict: %s", verdict)
 	}
 }
 
-func (t *Tools) AuditEvidence(hypothesisID, risks string) (string, error) {
-	defer t.RecordWork("AuditEvidence", time.Now())
-	_, err := t.ManageEvidence(PhaseDecision, "add", hypothesisID, "audit_report", risks, "pass", "L2", "auditor", "")
+func (t *Tools) AuditEvidence(ctx context.Context, hypothesisID, risks string) (string, error) {
+	defer t.RecordWork(ctx, "AuditEvidence", time.Now())
+	_, err := t.ManageEvidence(ctx, PhaseDecision, "add", hypothesisID, "audit_report", risks, "pass", "L2", "auditor", "")
 	return "Audit recorded for " + hypothesisID, err
 }
 
-func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil string) (string, error) {
-	defer t.RecordWork("ManageEvidence", time.Now())
+func (t *Tools) ManageEvidence(ctx context.Context, currentPhase Phase, action, targetID, evidenceType, content, verdict, assuranceLevel, carrierRef, validUntil string) (string, error) {
+	defer t.RecordWork(ctx, "ManageEvidence", time.Now())
 
 	if validUntil == "" && action != "check" {
 		validUntil = time.Now().AddDate(0, 0, 90).Format("2006-01-02")
 	}
-	ctx := context.Background()
 
 	if action == "check" {
 		if t.DB == nil {
@@ -463,19 +541,19 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 	if (normalizedVerdict == "pass") && shouldPromote {
 		switch currentPhase {
 		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "L1")
+			_, moveErr = t.MoveHypothesis(ctx, targetID, "L0", "L1")
 		case PhaseInduction:
 			if _, err := os.Stat(filepath.Join(t.GetFPFDir(), "knowledge", "L0", targetID+".md")); err == nil {
 				return "", fmt.Errorf("hypothesis %s is still in L0: run /q2-verify to promote it to L1 before testing", targetID)
 			}
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "L2")
+			_, moveErr = t.MoveHypothesis(ctx, targetID, "L1", "L2")
 		}
 	} else if normalizedVerdict == "fail" || normalizedVerdict == "refine" {
 		switch currentPhase {
 		case PhaseDeduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L0", "invalid")
+			_, moveErr = t.MoveHypothesis(ctx, targetID, "L0", "invalid")
 		case PhaseInduction:
-			_, moveErr = t.MoveHypothesis(targetID, "L1", "invalid")
+			_, moveErr = t.MoveHypothesis(ctx, targetID, "L1", "invalid")
 		}
 	}
 
@@ -505,12 +583,13 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 
 	if t.DB != nil {
 		if err := t.DB.AddEvidence(ctx, filename, targetID, evidenceType, content, normalizedVerdict, assuranceLevel, carrierRef, validUntil); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to add evidence to DB: %v\n", err)
+			t.Log.Warnf(ctx, "failed to add evidence to DB: %v", err)
 		}
 		if err := t.DB.Link(ctx, filename, targetID, "verifiedBy"); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to link evidence in DB: %v\n", err)
+			t.Log.Warnf(ctx, "failed to link evidence in DB: %v", err)
 		}
 	}
+	t.resourceUpdated(evidenceURI(targetID), holonURI(targetID))
 
 	if !shouldPromote && verdict == "PASS" {
 		return path + " (Evidence recorded, but Assurance Level insufficient for promotion)", nil
@@ -518,8 +597,8 @@ func (t *Tools) ManageEvidence(currentPhase Phase, action, targetID, evidenceTyp
 	return path, nil
 }
 
-func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle, newContent, scope string) (string, error) {
-	defer t.RecordWork("RefineLoopback", time.Now())
+func (t *Tools) RefineLoopback(ctx context.Context, currentPhase Phase, parentID, insight, newTitle, newContent, scope string) (string, error) {
+	defer t.RecordWork(ctx, "RefineLoopback", time.Now())
 
 	var parentLevel string
 	switch currentPhase {
@@ -531,12 +610,12 @@ func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle,
 		return "", fmt.Errorf("loopback not applicable from phase %s", currentPhase)
 	}
 
-	if _, err := t.MoveHypothesis(parentID, parentLevel, "invalid"); err != nil {
+	if _, err := t.MoveHypothesis(ctx, parentID, parentLevel, "invalid"); err != nil {
 		return "", fmt.Errorf("failed to move parent hypothesis to invalid: %v", err)
 	}
 
 	rationale := fmt.Sprintf(`{"source": "loopback", "parent_id": "%s", "insight": "%s"}`, parentID, insight)
-	childPath, err := t.ProposeHypothesis(newTitle, newContent, scope, "system", rationale, "", nil, 3)
+	childPath, err := t.ProposeHypothesis(ctx, newTitle, newContent, scope, "system", rationale, "", nil, 3, assurance.MinFormality)
 	if err != nil {
 		return "", fmt.Errorf("failed to create child hypothesis: %v", err)
 	}
@@ -550,8 +629,8 @@ func (t *Tools) RefineLoopback(currentPhase Phase, parentID, insight, newTitle,
 	return childPath, nil
 }
 
-func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, decisionContext, decision, rationale, consequences, characteristics string) (string, error) {
-	defer t.RecordWork("FinalizeDecision", time.Now())
+func (t *Tools) FinalizeDecision(ctx context.Context, title, winnerID string, rejectedIDs []string, decisionContext, decision, rationale, consequences, characteristics string) (string, error) {
+	defer t.RecordWork(ctx, "FinalizeDecision", time.Now())
 
 	body := fmt.Sprintf("\n# %s\n\n", title)
 	body += fmt.Sprintf("## Context\n%s\n\n", decisionContext)
@@ -574,21 +653,20 @@ func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, d
 	}
 
 	if err := WriteWithHash(drrPath, fields, body); err != nil {
-		t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "ERROR", map[string]string{"title": title}, err.Error())
+		t.AuditLog(ctx, "quint_decide", "finalize_decision", "agent", winnerID, "ERROR", map[string]string{"title": title}, err.Error())
 		return "", err
 	}
 
 	if t.DB != nil {
-		ctx := context.Background()
 		drrID := t.Slugify(title)
 		if err := t.DB.CreateHolon(ctx, drrID, "DRR", "", "DRR", title, body, "default", "", winnerID); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to create DRR holon in DB: %v\n", err)
+			t.Log.Warnf(ctx, "failed to create DRR holon in DB: %v", err)
 		}
 
 		// Create selects relation: DRR → winner
 		if winnerID != "" {
 			if err := t.createRelation(ctx, drrID, "selects", winnerID, 3); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create selects relation: %v\n", err)
+				t.Log.Warnf(ctx, "failed to create selects relation: %v", err)
 			}
 		}
 
@@ -596,74 +674,114 @@ func (t *Tools) FinalizeDecision(title, winnerID string, rejectedIDs []string, d
 		for _, rejID := range rejectedIDs {
 			if rejID != "" && rejID != winnerID {
 				if err := t.createRelation(ctx, drrID, "rejects", rejID, 3); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to create rejects relation to %s: %v\n", rejID, err)
+					t.Log.Warnf(ctx, "failed to create rejects relation to %s: %v", rejID, err)
 				}
 			}
 		}
 	}
 
 	if winnerID != "" {
-		_, err := t.MoveHypothesis(winnerID, "L1", "L2")
+		_, err := t.MoveHypothesis(ctx, winnerID, "L1", "L2")
 		if err != nil {
-			fmt.Printf("WARNING: Failed to move winner hypothesis %s to L2: %v\n", winnerID, err)
+			t.Log.Warnf(ctx, "failed to move winner hypothesis %s to L2: %v", winnerID, err)
 		}
 	}
 
-	t.AuditLog("quint_decide", "finalize_decision", "agent", winnerID, "SUCCESS", map[string]string{"title": title, "drr": drrName}, "")
+	t.AuditLog(ctx, "quint_decide", "finalize_decision", "agent", winnerID, "SUCCESS", map[string]string{"title": title, "drr": drrName}, "")
+	t.resourceUpdated(drrURI(t.Slugify(title)))
+	t.resourceListChanged()
 	return drrPath, nil
 }
 
-func (t *Tools) RunDecay() error {
-	defer t.RecordWork("RunDecay", time.Now())
+// RunDecay recalculates and caches R_eff for every holon, reporting progress per holon
+func (t *Tools) RunDecay(ctx context.Context) error {
+	defer t.RecordWork(ctx, "RunDecay", time.Now())
 	if t.DB == nil {
 		return fmt.Errorf("DB not initialized")
 	}
 
-	ctx := context.Background()
-	ids, err := t.DB.ListAllHolonIDs(ctx)
+	calc, err := t.calculator(ctx)
 	if err != nil {
 		return err
 	}
-
-	calc := assurance.New(t.DB.GetRawDB())
-	updatedCount := 0
-
-	for _, id := range ids {
-		_, err := calc.CalculateReliability(ctx, id)
-		if err != nil {
-			fmt.Printf("Error calculating R for %s: %v\n", id, err)
-			continue
+	before, _ := t.cachedScores(ctx)
+	batch, err := calc.NewBatch(ctx)
+	if err != nil {
+		return err
+	}
+	ids := batch.Graph().HolonIDs()
+	for i, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-		updatedCount++
+		if _, err := batch.Report(ctx, id); err != nil {
+			return err
+		}
+		reportProgress(ctx, float64(i+1), float64(len(ids)), "Recalculated "+id)
 	}
-
-	fmt.Printf("Decay update complete. Processed %d holons.\n", updatedCount)
+	if err := batch.WriteCache(ctx); err != nil {
+		return err
+	}
+	t.notifyScoreChanges(ctx, before)
 	return nil
 }
 
-func (t *Tools) VisualizeAudit(rootID string) (string, error) {
-	defer t.RecordWork("VisualizeAudit", time.Now())
+func (t *Tools) VisualizeAudit(ctx context.Context, rootID string) (string, error) {
+	result, err := t.AuditTree(ctx, rootID)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// AuditTree evaluates the assurance tree below rootID: componentOf/constituentOf
+// dependencies with their CL, and memberOf alternatives for visibility.
+func (t *Tools) AuditTree(ctx context.Context, rootID string) (*AuditTreeResult, error) {
+	defer t.RecordWork(ctx, "VisualizeAudit", time.Now())
 	if t.DB == nil {
-		return "", fmt.Errorf("DB not initialized")
+		return nil, fmt.Errorf("DB not initialized")
 	}
 
 	if rootID == "all" {
-		return "Please specify a root ID for the audit tree.", nil
+		return &AuditTreeResult{RootID: rootID, text: "Please specify a root ID for the audit tree."}, nil
 	}
 
-	calc := assurance.New(t.DB.GetRawDB())
-	return t.buildAuditTree(rootID, 0, calc)
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := calc.NewBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &AuditTreeResult{RootID: rootID}
+	tree, err := t.buildAuditTree(ctx, AuditNode{ID: rootID, Relation: "root"}, batch, result)
+	if err != nil {
+		return nil, err
+	}
+	result.text = tree
+	if err := batch.WriteCache(ctx); err != nil {
+		t.Log.Warnf(ctx, "failed to update R cache: %v", err)
+	}
+	return result, nil
 }
 
-func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calculator) (string, error) {
-	ctx := context.Background()
-	report, err := calc.CalculateReliability(ctx, holonID)
+// buildAuditTree renders node and its subtree, appending every evaluated node to result
+func (t *Tools) buildAuditTree(ctx context.Context, node AuditNode, batch *assurance.Batch, result *AuditTreeResult) (string, error) {
+	holonID := node.ID
+	report, err := batch.Report(ctx, holonID)
 	if err != nil {
 		return "", err
 	}
 
-	indent := strings.Repeat("  ", level)
-	tree := fmt.Sprintf("%s[%s R:%.2f] %s\n", indent, holonID, report.FinalScore, t.getHolonTitle(holonID))
+	node.Title = t.getHolonTitle(ctx, holonID)
+	node.R = report.FinalScore
+	node.Formality = report.Formality
+	node.Factors = report.Factors
+	result.Nodes = append(result.Nodes, node)
+
+	indent := strings.Repeat("  ", node.Depth)
+	tree := fmt.Sprintf("%s[%s R:%.2f F:%d] %s\n", indent, holonID, report.FinalScore, report.Formality, node.Title)
 
 	if len(report.Factors) > 0 {
 		for _, f := range report.Factors {
@@ -674,7 +792,7 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 	// Show componentOf/constituentOf dependencies (these propagate WLNK)
 	components, err := t.DB.GetComponentsOf(ctx, holonID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to query dependencies for %s: %v\n", holonID, err)
+		t.Log.Warnf(ctx, "failed to query dependencies for %s: %v", holonID, err)
 		return tree, nil
 	}
 
@@ -685,7 +803,8 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 		}
 		clStr := fmt.Sprintf("CL:%d", cl)
 		tree += fmt.Sprintf("%s  --(%s)-->\n", indent, clStr)
-		subTree, _ := t.buildAuditTree(c.SourceID, level+1, calc)
+		child := AuditNode{ID: c.SourceID, Parent: holonID, Relation: "componentOf", CL: int(cl), Depth: node.Depth + 1}
+		subTree, _ := t.buildAuditTree(ctx, child, batch, result)
 		tree += subTree
 	}
 
@@ -695,20 +814,29 @@ func (t *Tools) buildAuditTree(holonID string, level int, calc *assurance.Calcul
 	if err == nil && len(members) > 0 {
 		tree += fmt.Sprintf("%s  [members]\n", indent)
 		for _, m := range members {
-			memberReport, mErr := calc.CalculateReliability(ctx, m.SourceID)
+			memberReport, mErr := batch.Report(ctx, m.SourceID)
 			if mErr != nil {
 				tree += fmt.Sprintf("%s    - %s (error)\n", indent, m.SourceID)
 				continue
 			}
-			tree += fmt.Sprintf("%s    - [%s R:%.2f] %s\n", indent, m.SourceID, memberReport.FinalScore, t.getHolonTitle(m.SourceID))
+			member := AuditNode{
+				ID:        m.SourceID,
+				Title:     t.getHolonTitle(ctx, m.SourceID),
+				Parent:    holonID,
+				Relation:  "memberOf",
+				Depth:     node.Depth + 1,
+				R:         memberReport.FinalScore,
+				Formality: memberReport.Formality,
+			}
+			result.Nodes = append(result.Nodes, member)
+			tree += fmt.Sprintf("%s    - [%s R:%.2f F:%d] %s\n", indent, m.SourceID, memberReport.FinalScore, memberReport.Formality, member.Title)
 		}
 	}
 
 	return tree, nil
 }
 
-func (t *Tools) getHolonTitle(id string) string {
-	ctx := context.Background()
+func (t *Tools) getHolonTitle(ctx context.Context, id string) string {
 	title, err := t.DB.GetHolonTitle(ctx, id)
 	if err != nil || title == "" {
 		return id
@@ -716,7 +844,7 @@ func (t *Tools) getHolonTitle(id string) string {
 	return title
 }
 
-func (t *Tools) Actualize() (string, error) {
+func (t *Tools) Actualize(ctx context.Context) (string, error) {
 	var report strings.Builder
 	fpfDir := filepath.Join(t.RootDir, ".fpf")
 	quintDir := t.GetFPFDir()
@@ -746,7 +874,7 @@ func (t *Tools) Actualize() (string, error) {
 		report.WriteString("MIGRATION: Renamed to quint.db.\n")
 	}
 
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
 	cmd.Dir = t.RootDir
 	output, err := cmd.Output()
 	if err == nil {
@@ -761,7 +889,7 @@ func (t *Tools) Actualize() (string, error) {
 			}
 		} else if currentCommit != lastCommit {
 			report.WriteString(fmt.Sprintf("RECONCILIATION: Detected changes since %s\n", lastCommit))
-			diffCmd := exec.Command("git", "diff", "--name-status", lastCommit, "HEAD")
+			diffCmd := exec.CommandContext(ctx, "git", "diff", "--name-status", lastCommit, "HEAD")
 			diffCmd.Dir = t.RootDir
 			diffOutput, err := diffCmd.Output()
 			if err == nil {
@@ -785,69 +913,315 @@ func (t *Tools) Actualize() (string, error) {
 	return report.String(), nil
 }
 
-func (t *Tools) GetHolon(id string) (db.Holon, error) {
+func (t *Tools) GetHolon(ctx context.Context, id string) (db.Holon, error) {
 	if t.DB == nil {
 		return db.Holon{}, fmt.Errorf("DB not initialized")
 	}
-	return t.DB.GetHolon(context.Background(), id)
+	return t.DB.GetHolon(ctx, id)
+}
+
+func (t *Tools) CalculateR(ctx context.Context, holonID string) (string, error) {
+	result, err := t.Reliability(ctx, holonID)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
 }
 
-func (t *Tools) CalculateR(holonID string) (string, error) {
-	defer t.RecordWork("CalculateR", time.Now())
+// Reliability computes the R_eff breakdown for a holon
+func (t *Tools) Reliability(ctx context.Context, holonID string) (*ReliabilityResult, error) {
+	defer t.RecordWork(ctx, "CalculateR", time.Now())
 	if t.DB == nil {
-		return "", fmt.Errorf("DB not initialized")
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
 	}
+	report, err := calc.CalculateReliability(ctx, holonID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReliabilityResult{
+		HolonID: holonID,
+		R:       report.FinalScore,
+		Interval: IntervalResult{
+			Lower:       report.Interval.Lower,
+			Upper:       report.Interval.Upper,
+			Credibility: calc.Policy.Credibility,
+		},
+		SelfScore:     report.SelfScore,
+		Formality:     report.Formality,
+		SelfFormality: report.SelfFormality,
+		WeakestLink:   report.WeakestLink,
+		Scope:         report.Scope.String(),
+		DecayPenalty:  report.DecayPenalty,
+		PolicyVersion: report.PolicyVersion,
+		PenaltyMode:   string(calc.Policy.Penalty.Mode),
+		Factors:       report.Factors,
+	}
+	for _, w := range report.Waivers {
+		result.Waivers = append(result.Waivers, WaiverRef{EvidenceID: w.EvidenceID, HolonID: w.HolonID, Until: w.Until.Format("2006-01-02")})
+	}
+	return result, nil
+}
 
-	calc := assurance.New(t.DB.GetRawDB())
-	report, err := calc.CalculateReliability(context.Background(), holonID)
+func (t *Tools) CheckScope(ctx context.Context, holonID, slice string) (string, error) {
+	result, err := t.ScopeCheck(ctx, holonID, slice)
 	if err != nil {
 		return "", err
 	}
+	return result.Markdown(), nil
+}
 
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("## Reliability Report: %s\n\n", holonID))
-	result.WriteString(fmt.Sprintf("**R_eff: %.2f**\n", report.FinalScore))
-	result.WriteString(fmt.Sprintf("- Self Score: %.2f\n", report.SelfScore))
-	if report.WeakestLink != "" {
-		result.WriteString(fmt.Sprintf("- Weakest Link: %s\n", report.WeakestLink))
+// ScopeCheck tells whether a holon's effective scope (G) covers the given context slice.
+// For a DRR the check is made against the selected (winning) hypothesis.
+func (t *Tools) ScopeCheck(ctx context.Context, holonID, slice string) (*ScopeResult, error) {
+	defer t.RecordWork(ctx, "CheckScope", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
 	}
-	if report.DecayPenalty > 0 {
-		result.WriteString(fmt.Sprintf("- Decay Penalty: %.2f\n", report.DecayPenalty))
+
+	sliceScope, err := assurance.ParseScope(slice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slice: %v", err)
 	}
-	if len(report.Factors) > 0 {
-		result.WriteString("\n**Factors:**\n")
-		for _, f := range report.Factors {
-			result.WriteString(fmt.Sprintf("- %s\n", f))
+
+	targetID := holonID
+	holon, err := t.DB.GetHolon(ctx, holonID)
+	if err != nil {
+		return nil, fmt.Errorf("holon not found: %s", holonID)
+	}
+	if holon.Layer == "DRR" && holon.ParentID.Valid {
+		targetID = holon.ParentID.String
+	}
+
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report, err := calc.CalculateReliability(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := report.Scope.Check(sliceScope)
+	result := &ScopeResult{
+		HolonID:      holonID,
+		TargetID:     targetID,
+		Scope:        report.Scope.String(),
+		Slice:        sliceScope.String(),
+		OutsideScope: check.Mismatches,
+		EmptyScope:   report.Scope.IsEmpty(),
+	}
+	switch {
+	case !check.Applies:
+		result.Applies = "no"
+	case len(check.Unspecified) > 0:
+		result.Applies = "conditional"
+	default:
+		result.Applies = "yes"
+	}
+	for _, d := range check.Unspecified {
+		result.Unspecified = append(result.Unspecified, UnspecifiedDimension{Dimension: d, Allowed: report.Scope[d]})
+	}
+	return result, nil
+}
+
+// whatIfSensitivityLimit caps the sensitivity table shown when no changes are given
+const whatIfSensitivityLimit = 10
+
+func (t *Tools) WhatIf(ctx context.Context, holonID, changesJSON string) (string, error) {
+	result, err := t.Simulate(ctx, holonID, changesJSON)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// Simulate applies hypothetical changes (changesJSON: array of assurance.Change) to an
+// in-memory copy of the graph and reports how R_eff of holonID would move. Without
+// changes it runs a sensitivity sweep over the holon's dependencies. Nothing is persisted.
+func (t *Tools) Simulate(ctx context.Context, holonID, changesJSON string) (*WhatIfResult, error) {
+	defer t.RecordWork(ctx, "WhatIf", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	var changes []assurance.Change
+	if strings.TrimSpace(changesJSON) != "" {
+		if err := json.Unmarshal([]byte(changesJSON), &changes); err != nil {
+			return nil, fmt.Errorf("invalid changes: %v", err)
+		}
+	}
+
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := assurance.LoadGraph(ctx, t.DB.GetRawDB())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		results, err := calc.Sensitivity(ctx, graph, holonID, whatIfSensitivityLimit)
+		if err != nil {
+			return nil, err
+		}
+		base, err := calc.Evaluate(ctx, graph, holonID)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &WhatIfResult{HolonID: holonID, Before: snapshot(base)}
+		for _, r := range results {
+			result.Sensitivity = append(result.Sensitivity, SensitivityEntry{
+				Change:      r.Changes[0].String(),
+				R:           r.After.FinalScore,
+				Delta:       r.Delta,
+				WeakestLink: r.After.WeakestLink,
+			})
+		}
+		return result, nil
+	}
+
+	whatIf, err := calc.WhatIf(ctx, graph, holonID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	after := snapshot(whatIf.After)
+	result := &WhatIfResult{
+		HolonID: holonID,
+		Before:  snapshot(whatIf.Before),
+		After:   &after,
+		Delta:   whatIf.Delta,
+	}
+	for _, ch := range changes {
+		result.Changes = append(result.Changes, ch.String())
+	}
+	return result, nil
+}
+
+func snapshot(report *assurance.AssuranceReport) ScoreSnapshot {
+	return ScoreSnapshot{
+		R:            report.FinalScore,
+		SelfScore:    report.SelfScore,
+		WeakestLink:  report.WeakestLink,
+		DecayPenalty: report.DecayPenalty,
+	}
+}
+
+func (t *Tools) CheckGraph(ctx context.Context) (string, error) {
+	result, err := t.GraphIntegrity(ctx)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
+}
+
+// GraphIntegrity reports integrity problems in the knowledge graph
+func (t *Tools) GraphIntegrity(ctx context.Context) (*GraphCheckResult, error) {
+	defer t.RecordWork(ctx, "CheckGraph", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	check, err := assurance.CheckGraph(ctx, t.DB.GetRawDB())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GraphCheckResult{
+		Holons:    check.Holons,
+		Relations: check.Relations,
+		Evidence:  check.Evidence,
+		OK:        check.OK(),
+		Cycles:    check.Cycles,
+	}
+	for _, r := range check.DanglingRelations {
+		result.DanglingRelations = append(result.DanglingRelations, DanglingRelationRef{SourceID: r.SourceID, Relation: r.RelationType, TargetID: r.TargetID, Missing: r.Missing})
+	}
+	for _, e := range check.OrphanedEvidence {
+		result.OrphanedEvidence = append(result.OrphanedEvidence, OrphanedEvidenceRef{EvidenceID: e.EvidenceID, HolonID: e.HolonID})
+	}
+
+	if !result.OK {
+		t.AuditLog(ctx, "quint_check_graph", "check_graph", "user", "", "SUCCESS", nil,
+			fmt.Sprintf("cycles=%d dangling=%d orphaned=%d", len(check.Cycles), len(check.DanglingRelations), len(check.OrphanedEvidence)))
+	}
+	return result, nil
+}
+
+// Search ranks holons, DRRs and evidence by full-text relevance to text
+func (t *Tools) Search(ctx context.Context, text string, limit int) (*SearchResult, error) {
+	defer t.RecordWork(ctx, "Search", time.Now())
+	if t.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+
+	hits, err := t.DB.Search(ctx, text, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	result := &SearchResult{Query: text, Hits: []SearchHit{}}
+	for _, h := range hits {
+		hit := SearchHit{
+			HolonID: h.HolonID,
+			Title:   h.Title,
+			Layer:   h.Layer,
+			Source:  h.Source,
+			Snippet: h.Snippet,
+			Score:   -h.Rank,
+			URI:     holonURI(h.HolonID),
 		}
+		switch h.Source {
+		case "drr":
+			hit.URI = drrURI(h.HolonID)
+		case "evidence":
+			hit.EvidenceID = h.SourceID
+		}
+		result.Hits = append(result.Hits, hit)
 	}
+	return result, nil
+}
 
-	return result.String(), nil
+func (t *Tools) CheckDecay(ctx context.Context, deprecate, waiveID, waiveUntil, waiveRationale string) (string, error) {
+	result, err := t.Decay(ctx, deprecate, waiveID, waiveUntil, waiveRationale)
+	if err != nil {
+		return "", err
+	}
+	return result.Markdown(), nil
 }
 
-func (t *Tools) CheckDecay(deprecate, waiveID, waiveUntil, waiveRationale string) (string, error) {
-	defer t.RecordWork("CheckDecay", time.Now())
+// Decay reports evidence freshness, or deprecates a holon / waives stale evidence
+func (t *Tools) Decay(ctx context.Context, deprecate, waiveID, waiveUntil, waiveRationale string) (*DecayResult, error) {
+	defer t.RecordWork(ctx, "CheckDecay", time.Now())
 	if t.DB == nil {
-		return "", fmt.Errorf("DB not initialized")
+		return nil, fmt.Errorf("DB not initialized")
 	}
 
 	switch {
 	case deprecate != "":
-		return t.deprecateHolon(deprecate)
+		return t.deprecateHolon(ctx, deprecate)
 	case waiveID != "":
 		if waiveUntil == "" || waiveRationale == "" {
-			return "", fmt.Errorf("waive requires both --until and --rationale parameters")
+			return nil, fmt.Errorf("waive requires both --until and --rationale parameters")
 		}
-		return t.createWaiver(waiveID, waiveUntil, waiveRationale)
+		return t.createWaiver(ctx, waiveID, waiveUntil, waiveRationale)
 	default:
-		return t.generateFreshnessReport()
+		return t.generateFreshnessReport(ctx)
 	}
 }
 
-func (t *Tools) deprecateHolon(holonID string) (string, error) {
-	ctx := context.Background()
+func (t *Tools) deprecateHolon(ctx context.Context, holonID string) (*DecayResult, error) {
 	holon, err := t.DB.GetHolon(ctx, holonID)
 	if err != nil {
-		return "", fmt.Errorf("holon not found: %s", holonID)
+		return nil, fmt.Errorf("holon not found: %s", holonID)
 	}
 
 	var newLayer string
@@ -857,58 +1231,102 @@ func (t *Tools) deprecateHolon(holonID string) (string, error) {
 	case "L1":
 		newLayer = "L0"
 	default:
-		return "", fmt.Errorf("cannot deprecate %s from %s (only L2 and L1 can be deprecated)", holonID, holon.Layer)
+		return nil, fmt.Errorf("cannot deprecate %s from %s (only L2 and L1 can be deprecated)", holonID, holon.Layer)
 	}
 
-	if _, err := t.MoveHypothesis(holonID, holon.Layer, newLayer); err != nil {
-		return "", err
+	if _, err := t.MoveHypothesis(ctx, holonID, holon.Layer, newLayer); err != nil {
+		return nil, err
 	}
 
-	t.AuditLog("quint_check_decay", "deprecate", "user", holonID, "SUCCESS",
+	t.AuditLog(ctx, "quint_check_decay", "deprecate", "user", holonID, "SUCCESS",
 		map[string]string{"from": holon.Layer, "to": newLayer}, "Evidence expired, holon deprecated")
 
-	return fmt.Sprintf("Deprecated: %s %s → %s\n\nThis decision now requires re-evaluation.\nNext step: Run /q1-hypothesize to explore alternatives.", holonID, holon.Layer, newLayer), nil
+	return &DecayResult{
+		Action:     "deprecate",
+		Deprecated: &LayerMove{HolonID: holonID, From: holon.Layer, To: newLayer},
+	}, nil
 }
 
-func (t *Tools) createWaiver(evidenceID, until, rationale string) (string, error) {
-	ctx := context.Background()
+func (t *Tools) createWaiver(ctx context.Context, evidenceID, until, rationale string) (*DecayResult, error) {
 
-	_, err := t.DB.GetEvidenceByID(ctx, evidenceID)
+	evidence, err := t.DB.GetEvidenceByID(ctx, evidenceID)
 	if err != nil {
-		return "", fmt.Errorf("evidence not found: %s", evidenceID)
+		return nil, fmt.Errorf("evidence not found: %s", evidenceID)
 	}
 
 	untilTime, err := time.Parse("2006-01-02", until)
 	if err != nil {
 		untilTime, err = time.Parse(time.RFC3339, until)
 		if err != nil {
-			return "", fmt.Errorf("invalid date format: %s (use YYYY-MM-DD or RFC3339)", until)
+			return nil, fmt.Errorf("invalid date format: %s (use YYYY-MM-DD or RFC3339)", until)
 		}
 	}
 
 	if untilTime.Before(time.Now()) {
-		return "", fmt.Errorf("waive_until must be a future date")
+		return nil, fmt.Errorf("waive_until must be a future date")
 	}
 
 	id := uuid.New().String()
 	if err := t.DB.CreateWaiver(ctx, id, evidenceID, "user", untilTime, rationale); err != nil {
-		return "", fmt.Errorf("failed to create waiver: %v", err)
+		return nil, fmt.Errorf("failed to create waiver: %v", err)
 	}
 
-	t.AuditLog("quint_check_decay", "waive", "user", evidenceID, "SUCCESS",
+	t.AuditLog(ctx, "quint_check_decay", "waive", "user", evidenceID, "SUCCESS",
 		map[string]string{"until": until, "rationale": rationale}, "")
+	t.resourceUpdated(evidenceURI(evidence.HolonID), holonURI(evidence.HolonID))
+
+	return &DecayResult{
+		Action: "waive",
+		Waiver: &ActiveWaiver{
+			EvidenceID:      evidenceID,
+			HolonID:         evidence.HolonID,
+			WaivedUntil:     until,
+			WaivedBy:        "user",
+			Rationale:       rationale,
+			DaysUntilExpiry: int(time.Until(untilTime).Hours() / 24),
+		},
+	}, nil
+}
+
+// decayForecastDays is how far ahead the freshness report projects R_eff under decay
+const decayForecastDays = 90
+
+// decayForecast lists L2 holons whose R_eff is above the assurance threshold today
+// but will fall below it within decayForecastDays as their evidence decays.
+func (t *Tools) decayForecast(ctx context.Context, threshold float64) ([]DecayingHolon, error) {
+	calc, err := t.calculator(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return fmt.Sprintf(`Waiver recorded:
-- Evidence: %s
-- Waived until: %s
-- Rationale: %s
+	holons, err := t.DB.ListHolonsByLayer(ctx, "L2")
+	if err != nil {
+		return nil, err
+	}
+	graph, err := assurance.LoadGraph(ctx, t.DB.GetRawDB())
+	if err != nil {
+		return nil, err
+	}
 
-⚠️ This evidence returns to EXPIRED status after %s.
-   Set a reminder to run /q3-validate before then.`, evidenceID, until, rationale, until), nil
+	forecast := calc.NewForecast(graph, decayForecastDays)
+	var decaying []DecayingHolon
+	for i, h := range holons {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		days, found, err := forecast.DaysUntilBelow(ctx, h.ID, threshold)
+		if err != nil {
+			return nil, err
+		}
+		reportProgress(ctx, float64(i+1), float64(len(holons)), "Forecast "+h.ID)
+		if found {
+			decaying = append(decaying, DecayingHolon{HolonID: h.ID, Title: h.Title, DaysUntilBelow: days})
+		}
+	}
+	return decaying, nil
 }
 
-func (t *Tools) generateFreshnessReport() (string, error) {
-	ctx := context.Background()
+func (t *Tools) generateFreshnessReport(ctx context.Context) (*DecayResult, error) {
 	rawDB := t.DB.GetRawDB()
 
 	rows, err := rawDB.QueryContext(ctx, `
@@ -932,30 +1350,24 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 		ORDER BY h.id, days_overdue DESC
 	`)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer rows.Close() //nolint:errcheck
 
-	type evidenceInfo struct {
-		ID          string
-		Type        string
-		DaysOverdue int
-	}
-
-	staleHolons := make(map[string][]evidenceInfo)
-	holonTitles := make(map[string]string)
-	holonLayers := make(map[string]string)
-
+	result := &DecayResult{Action: "report"}
 	for rows.Next() {
 		var evidenceID, holonID, title, layer, evidenceType string
 		var daysOverdue int
 		if err := rows.Scan(&evidenceID, &holonID, &title, &layer, &evidenceType, &daysOverdue); err != nil {
 			continue
 		}
-		holonTitles[holonID] = title
-		holonLayers[holonID] = layer
-		staleHolons[holonID] = append(staleHolons[holonID], evidenceInfo{
-			ID:          evidenceID,
+		// Rows are ordered by holon, so a holon's evidence is contiguous
+		if n := len(result.Stale); n == 0 || result.Stale[n-1].HolonID != holonID {
+			result.Stale = append(result.Stale, StaleHolon{HolonID: holonID, Title: title, Layer: layer})
+		}
+		last := &result.Stale[len(result.Stale)-1]
+		last.Evidence = append(last.Evidence, StaleEvidence{
+			EvidenceID:  evidenceID,
 			Type:        evidenceType,
 			DaysOverdue: daysOverdue,
 		})
@@ -971,68 +1383,27 @@ func (t *Tools) generateFreshnessReport() (string, error) {
 		ORDER BY w.waived_until ASC
 	`)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer waivedRows.Close() //nolint:errcheck
 
-	type waiverInfo struct {
-		EvidenceID      string
-		HolonID         string
-		HolonTitle      string
-		WaivedUntil     string
-		WaivedBy        string
-		Rationale       string
-		DaysUntilExpiry int
-	}
-
-	var activeWaivers []waiverInfo
 	for waivedRows.Next() {
-		var info waiverInfo
+		var info ActiveWaiver
 		if err := waivedRows.Scan(&info.EvidenceID, &info.HolonID, &info.HolonTitle, &info.WaivedUntil, &info.WaivedBy, &info.Rationale, &info.DaysUntilExpiry); err != nil {
 			continue
 		}
-		activeWaivers = append(activeWaivers, info)
-	}
-
-	var result strings.Builder
-	result.WriteString("## Evidence Freshness Report\n\n")
-
-	if len(staleHolons) == 0 {
-		result.WriteString("### All holons FRESH ✓\n\nNo expired evidence found.\n")
-	} else {
-		result.WriteString(fmt.Sprintf("### STALE (%d holons require action)\n\n", len(staleHolons)))
-
-		for holonID, evidenceItems := range staleHolons {
-			result.WriteString(fmt.Sprintf("#### %s (%s)\n", holonTitles[holonID], holonLayers[holonID]))
-			result.WriteString("| ID | Type | Status | Details |\n")
-			result.WriteString("|-----|------|--------|--------|\n")
-			for _, item := range evidenceItems {
-				result.WriteString(fmt.Sprintf("| %s | %s | EXPIRED | %d days overdue |\n", item.ID, item.Type, item.DaysOverdue))
-			}
-			result.WriteString("\nActions:\n")
-			result.WriteString(fmt.Sprintf("  → /q3-validate %s (refresh)\n", holonID))
-			result.WriteString(fmt.Sprintf("  → /q-decay --deprecate %s (downgrade)\n", holonID))
-			result.WriteString("  → /q-decay --waive <evidence_id> --until <date> --rationale \"...\"\n\n")
+		if len(info.WaivedUntil) > 10 {
+			info.WaivedUntil = info.WaivedUntil[:10]
 		}
+		result.Waivers = append(result.Waivers, info)
 	}
 
-	if len(activeWaivers) > 0 {
-		result.WriteString("---\n\n### WAIVED (temporary risk acceptance)\n\n")
-		result.WriteString("| Holon | Evidence | Waived Until | By | Rationale |\n")
-		result.WriteString("|-------|----------|--------------|----|-----------|\n")
-		for _, w := range activeWaivers {
-			waivedUntilShort := w.WaivedUntil
-			if len(waivedUntilShort) > 10 {
-				waivedUntilShort = waivedUntilShort[:10]
-			}
-			result.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", w.HolonTitle, w.EvidenceID, waivedUntilShort, w.WaivedBy, w.Rationale))
-		}
-		for _, w := range activeWaivers {
-			if w.DaysUntilExpiry <= 30 {
-				result.WriteString(fmt.Sprintf("\n⚠️ Waiver for %s expires in %d days\n", w.EvidenceID, w.DaysUntilExpiry))
-			}
-		}
+	result.Threshold = t.FSM.GetAssuranceThreshold()
+	result.ForecastDays = decayForecastDays
+	result.Decaying, err = t.decayForecast(ctx, result.Threshold)
+	if err != nil {
+		return nil, err
 	}
 
-	return result.String(), nil
+	return result, nil
 }
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/m0n0x41d/quint-code/db"
 )
@@ -94,7 +95,7 @@ func TestProposeHypothesis(t *testing.T) {
 	kind := "system"
 	rationale := "This is the rationale."
 
-	path, err := tools.ProposeHypothesis(title, content, scope, kind, rationale, "", nil, 3)
+	path, err := tools.ProposeHypothesis(t.Context(), title, content, scope, kind, rationale, "", nil, 3, 0)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
 	}
@@ -191,7 +192,7 @@ func TestManageEvidence(t *testing.T) {
 				}
 			}
 
-			evidencePath, err := tools.ManageEvidence(tt.currentPhase, "add", tt.targetID, tt.evidenceType, tt.content, tt.verdict, tt.assuranceLevel, "file://carrier", "2025-12-31")
+			evidencePath, err := tools.ManageEvidence(t.Context(), tt.currentPhase, "add", tt.targetID, tt.evidenceType, tt.content, tt.verdict, tt.assuranceLevel, "file://carrier", "2025-12-31")
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("ManageEvidence() error = %v, expectErr %v", err, tt.expectErr)
@@ -241,7 +242,7 @@ func TestRefineLoopback(t *testing.T) {
 	newContent := "This is the refined content."
 	scope := "system"
 
-	childPath, err := tools.RefineLoopback(fsm.State.Phase, parentID, insight, newTitle, newContent, scope)
+	childPath, err := tools.RefineLoopback(t.Context(), fsm.State.Phase, parentID, insight, newTitle, newContent, scope)
 	if err != nil {
 		t.Fatalf("RefineLoopback failed: %v", err)
 	}
@@ -283,7 +284,7 @@ func TestFinalizeDecision(t *testing.T) {
 	title := "Final Project Decision"
 	content := "This is the DRR content for the decision."
 
-	drrPath, err := tools.FinalizeDecision(title, winnerID, nil, "Context", content, "Rationale", "Consequences", "Characteristics")
+	drrPath, err := tools.FinalizeDecision(t.Context(), title, winnerID, nil, "Context", content, "Rationale", "Consequences", "Characteristics")
 	if err != nil {
 		t.Fatalf("FinalizeDecision failed: %v", err)
 	}
@@ -333,7 +334,7 @@ func TestVerifyHypothesis(t *testing.T) {
 
 	// Case 1: PASS -> Promote to L1
 	fsm.State.Phase = PhaseDeduction
-	msg, err := tools.VerifyHypothesis(hypoID, `{"check":"ok"}`, "PASS")
+	msg, err := tools.VerifyHypothesis(t.Context(), hypoID, `{"check":"ok"}`, "PASS", -1)
 	if err != nil {
 		t.Errorf("VerifyHypothesis(PASS) failed: %v", err)
 	}
@@ -352,7 +353,7 @@ func TestVerifyHypothesis(t *testing.T) {
 		t.Fatalf("Failed to create dummy L0 hypothesis 2: %v", err)
 	}
 
-	msg, err = tools.VerifyHypothesis(hypoID2, `{"check":"bad"}`, "FAIL")
+	msg, err = tools.VerifyHypothesis(t.Context(), hypoID2, `{"check":"bad"}`, "FAIL", -1)
 	if err != nil {
 		t.Errorf("VerifyHypothesis(FAIL) failed: %v", err)
 	}
@@ -365,6 +366,40 @@ func TestVerifyHypothesis(t *testing.T) {
 	}
 }
 
+func TestFormality_ProposeAndVerify(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	ctx := context.Background()
+	fsm.State.Phase = PhaseAbduction
+
+	if _, err := tools.ProposeHypothesis(ctx, "Too Formal", "Content", "global", "system", "{}", "", nil, 3, 10); err == nil {
+		t.Error("Expected error for formality above F9")
+	}
+
+	if _, err := tools.ProposeHypothesis(ctx, "Formal Claim", "Content", "global", "system", "{}", "", nil, 3, 2); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	holon, err := tools.DB.GetHolon(ctx, "formal-claim")
+	if err != nil {
+		t.Fatalf("GetHolon failed: %v", err)
+	}
+	if holon.Formality.Int64 != 2 {
+		t.Errorf("Expected F2 after propose, got F%d", holon.Formality.Int64)
+	}
+
+	fsm.State.Phase = PhaseDeduction
+	if _, err := tools.VerifyHypothesis(ctx, "formal-claim", `{"check":"ok"}`, "PASS", 6); err != nil {
+		t.Fatalf("VerifyHypothesis failed: %v", err)
+	}
+
+	result, err := tools.CalculateR(ctx, "formal-claim")
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "Formality: F6") {
+		t.Errorf("Expected 'Formality: F6' in output, got: %s", result)
+	}
+}
+
 func TestAuditEvidence(t *testing.T) {
 
 	tools, fsm, _ := setupTools(t)
@@ -379,9 +414,9 @@ func TestAuditEvidence(t *testing.T) {
 	// We need to ensure DB is happy if it checks constraints.
 
 	// In tools.go, AuditEvidence calls:
-	// t.ManageEvidence(PhaseDecision, "add", hypothesisID, "audit_report", risks, "PASS", "L2", "auditor", "")
+	// t.ManageEvidence(t.Context(), PhaseDecision, "add", hypothesisID, "audit_report", risks, "PASS", "L2", "auditor", "")
 
-	msg, err := tools.AuditEvidence(hypoID, "Risk analysis content")
+	msg, err := tools.AuditEvidence(t.Context(), hypoID, "Risk analysis content")
 	if err != nil {
 		t.Errorf("AuditEvidence failed: %v", err)
 	}
@@ -411,7 +446,7 @@ func TestCalculateR(t *testing.T) {
 	}
 
 	// Calculate R
-	result, err := tools.CalculateR("calc-r-test")
+	result, err := tools.CalculateR(ctx, "calc-r-test")
 	if err != nil {
 		t.Fatalf("CalculateR failed: %v", err)
 	}
@@ -445,7 +480,7 @@ func TestCalculateR_WithDecay(t *testing.T) {
 	}
 
 	// Calculate R
-	result, err := tools.CalculateR("decay-r-test")
+	result, err := tools.CalculateR(ctx, "decay-r-test")
 	if err != nil {
 		t.Fatalf("CalculateR failed: %v", err)
 	}
@@ -456,6 +491,49 @@ func TestCalculateR_WithDecay(t *testing.T) {
 	}
 }
 
+func TestCheckScope(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	ctx := context.Background()
+	fsm.State.Phase = PhaseAbduction
+
+	if err := tools.DB.CreateHolon(ctx, "payments-db", "hypothesis", "system", "L1", "Payments DB", "Content", "default", "service=payments; env=prod,staging", ""); err != nil {
+		t.Fatalf("Failed to create holon: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis(ctx, "Webhook Retry", "Retry webhooks", "env=prod", "system", "{}", "", []string{"payments-db"}, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if err := tools.DB.CreateHolon(ctx, "webhook-decision", "DRR", "", "DRR", "Webhook Decision", "Content", "default", "", "webhook-retry"); err != nil {
+		t.Fatalf("Failed to create DRR holon: %v", err)
+	}
+
+	tests := []struct {
+		holonID  string
+		slice    string
+		expected string
+	}{
+		{"webhook-retry", "service=payments; env=prod", "Applies: YES"},
+		{"webhook-retry", "service=payments; env=staging", "Applies: NO"},
+		{"webhook-retry", "env=prod", "Applies: CONDITIONAL"},
+		{"webhook-decision", "service=billing; env=prod", "Applies: NO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.holonID+" "+tt.slice, func(t *testing.T) {
+			result, err := tools.CheckScope(ctx, tt.holonID, tt.slice)
+			if err != nil {
+				t.Fatalf("CheckScope failed: %v", err)
+			}
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("Expected %q in output, got: %s", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := tools.CheckScope(ctx, "webhook-retry", "just words"); err == nil {
+		t.Error("Expected error for unstructured slice")
+	}
+}
+
 func TestCheckDecay_NoExpired(t *testing.T) {
 	tools, _, _ := setupTools(t)
 	ctx := context.Background()
@@ -473,7 +551,7 @@ func TestCheckDecay_NoExpired(t *testing.T) {
 	}
 
 	// Check decay (freshness report mode - all empty params)
-	result, err := tools.CheckDecay("", "", "", "")
+	result, err := tools.CheckDecay(ctx, "", "", "", "")
 	if err != nil {
 		t.Fatalf("CheckDecay failed: %v", err)
 	}
@@ -501,7 +579,7 @@ func TestCheckDecay_WithExpired(t *testing.T) {
 	}
 
 	// Check decay (freshness report mode - all empty params)
-	result, err := tools.CheckDecay("", "", "", "")
+	result, err := tools.CheckDecay(ctx, "", "", "", "")
 	if err != nil {
 		t.Fatalf("CheckDecay failed: %v", err)
 	}
@@ -515,6 +593,39 @@ func TestCheckDecay_WithExpired(t *testing.T) {
 	}
 }
 
+func TestCheckDecay_Forecast(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	// Linear decay: "test" evidence loses its score over 30 days after valid_until
+	policy := `{"decay": {"test": {"curve": "linear", "window_days": 30}}}`
+	if err := os.WriteFile(filepath.Join(tools.GetFPFDir(), "assurance.json"), []byte(policy), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+
+	err := tools.DB.CreateHolon(ctx, "decaying-holon", "hypothesis", "system", "L2", "Decaying Holon", "Content", "ctx", "global", "")
+	if err != nil {
+		t.Fatalf("Failed to create holon: %v", err)
+	}
+	validUntil := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	err = tools.DB.AddEvidence(ctx, "e-decaying", "decaying-holon", "test", "Benchmark", "pass", "L2", "test-runner", validUntil)
+	if err != nil {
+		t.Fatalf("Failed to add evidence: %v", err)
+	}
+
+	result, err := tools.CheckDecay(ctx, "", "", "", "")
+	if err != nil {
+		t.Fatalf("CheckDecay failed: %v", err)
+	}
+
+	if !strings.Contains(result, "DECAYING") || !strings.Contains(result, "decaying-holon") {
+		t.Errorf("Expected decay forecast for decaying-holon, got: %s", result)
+	}
+	if !strings.Contains(result, "All holons FRESH") {
+		t.Errorf("Expected holon to still be fresh today, got: %s", result)
+	}
+}
+
 func TestCheckDecay_Deprecate(t *testing.T) {
 	tools, _, _ := setupTools(t)
 	ctx := context.Background()
@@ -535,7 +646,7 @@ func TestCheckDecay_Deprecate(t *testing.T) {
 	}
 
 	// Deprecate (L2 -> L1)
-	result, err := tools.CheckDecay(holonID, "", "", "")
+	result, err := tools.CheckDecay(ctx, holonID, "", "", "")
 	if err != nil {
 		t.Fatalf("CheckDecay deprecate failed: %v", err)
 	}
@@ -577,7 +688,7 @@ func TestCheckDecay_Waive(t *testing.T) {
 	}
 
 	// Verify initially shows as stale
-	result, err := tools.CheckDecay("", "", "", "")
+	result, err := tools.CheckDecay(ctx, "", "", "", "")
 	if err != nil {
 		t.Fatalf("CheckDecay failed: %v", err)
 	}
@@ -588,7 +699,7 @@ func TestCheckDecay_Waive(t *testing.T) {
 	// Waive the evidence
 	futureDate := "2099-12-31"
 	rationale := "Test waiver"
-	result, err = tools.CheckDecay("", evidenceID, futureDate, rationale)
+	result, err = tools.CheckDecay(ctx, "", evidenceID, futureDate, rationale)
 	if err != nil {
 		t.Fatalf("CheckDecay waive failed: %v", err)
 	}
@@ -601,7 +712,7 @@ func TestCheckDecay_Waive(t *testing.T) {
 	}
 
 	// Check that it no longer shows as stale
-	result, err = tools.CheckDecay("", "", "", "")
+	result, err = tools.CheckDecay(ctx, "", "", "", "")
 	if err != nil {
 		t.Fatalf("CheckDecay report failed: %v", err)
 	}
@@ -610,19 +721,31 @@ func TestCheckDecay_Waive(t *testing.T) {
 	if strings.Contains(result, holonID) && strings.Contains(result, "STALE") && !strings.Contains(result, "WAIVED") {
 		t.Errorf("Expected waived evidence to not show as STALE, got: %s", result)
 	}
+
+	// The waiver also suspends decay in the reliability calculation
+	result, err = tools.CalculateR(ctx, holonID)
+	if err != nil {
+		t.Fatalf("CalculateR failed: %v", err)
+	}
+	if !strings.Contains(result, "R_eff: 1.00") {
+		t.Errorf("Expected waived evidence to keep R_eff 1.00, got: %s", result)
+	}
+	if !strings.Contains(result, "Waived: "+evidenceID) || !strings.Contains(result, "2099-12-31") {
+		t.Errorf("Expected waiver and expiry in report, got: %s", result)
+	}
 }
 
 func TestCheckDecay_WaiveMissingParams(t *testing.T) {
 	tools, _, _ := setupTools(t)
 
 	// Waive without until date
-	_, err := tools.CheckDecay("", "some-evidence", "", "some rationale")
+	_, err := tools.CheckDecay(t.Context(), "", "some-evidence", "", "some rationale")
 	if err == nil {
 		t.Error("Expected error when waive_until is missing")
 	}
 
 	// Waive without rationale
-	_, err = tools.CheckDecay("", "some-evidence", "2099-12-31", "")
+	_, err = tools.CheckDecay(t.Context(), "", "some-evidence", "2099-12-31", "")
 	if err == nil {
 		t.Error("Expected error when rationale is missing")
 	}
@@ -640,7 +763,7 @@ func TestCheckDecay_DeprecateL0Fails(t *testing.T) {
 	}
 
 	// Try to deprecate L0 - should fail
-	_, err = tools.CheckDecay(holonID, "", "", "")
+	_, err = tools.CheckDecay(ctx, holonID, "", "", "")
 	if err == nil {
 		t.Error("Expected error when deprecating L0 holon")
 	}
@@ -666,7 +789,7 @@ func TestVisualizeAudit(t *testing.T) {
 	}
 
 	// Visualize audit
-	result, err := tools.VisualizeAudit("audit-viz-test")
+	result, err := tools.VisualizeAudit(ctx, "audit-viz-test")
 	if err != nil {
 		t.Fatalf("VisualizeAudit failed: %v", err)
 	}
@@ -692,7 +815,7 @@ func TestPropose_WithDecisionContext(t *testing.T) {
 	}
 
 	// Propose hypothesis with decision_context
-	_, err = tools.ProposeHypothesis(
+	_, err = tools.ProposeHypothesis(ctx,
 		"Use Redis",
 		"Use Redis for caching",
 		"backend",
@@ -701,6 +824,7 @@ func TestPropose_WithDecisionContext(t *testing.T) {
 		"caching-decision", // decision_context
 		nil,                // no depends_on
 		3,
+		0,
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
@@ -739,7 +863,7 @@ func TestPropose_WithDependsOn(t *testing.T) {
 	}
 
 	// Propose hypothesis with depends_on
-	_, err = tools.ProposeHypothesis(
+	_, err = tools.ProposeHypothesis(ctx,
 		"API Gateway",
 		"Gateway with auth and rate limiting",
 		"external traffic",
@@ -748,6 +872,7 @@ func TestPropose_WithDependsOn(t *testing.T) {
 		"",                                      // no decision_context
 		[]string{"auth-module", "rate-limiter"}, // depends_on
 		3,                                       // CL3
+		0,                                       // F0
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
@@ -781,7 +906,7 @@ func TestPropose_CycleDetection(t *testing.T) {
 	}
 
 	// Create holon B that depends on A
-	_, err = tools.ProposeHypothesis("Holon B", "B depends on A", "global", "system", "{}", "", []string{"holon-a"}, 3)
+	_, err = tools.ProposeHypothesis(ctx, "Holon B", "B depends on A", "global", "system", "{}", "", []string{"holon-a"}, 3, 0)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for B failed: %v", err)
 	}
@@ -795,7 +920,7 @@ func TestPropose_CycleDetection(t *testing.T) {
 
 	// Try to make A depend on B (would create cycle since B already depends on A)
 	// This should be skipped with a warning, not error
-	_, err = tools.ProposeHypothesis("Holon C Cyclic", "C tries to depend on B", "global", "system", "{}", "", []string{"holon-b"}, 3)
+	_, err = tools.ProposeHypothesis(ctx, "Holon C Cyclic", "C tries to depend on B", "global", "system", "{}", "", []string{"holon-b"}, 3, 0)
 	// Should NOT error - cycles are skipped with warning
 	if err != nil {
 		t.Fatalf("ProposeHypothesis should not error on cycle, got: %v", err)
@@ -825,7 +950,7 @@ func TestPropose_InvalidDependency(t *testing.T) {
 	fsm.State.Phase = PhaseAbduction
 
 	// Propose hypothesis with non-existent dependency
-	_, err := tools.ProposeHypothesis(
+	_, err := tools.ProposeHypothesis(t.Context(),
 		"Orphan Hypo",
 		"Depends on non-existent holon",
 		"global",
@@ -834,6 +959,7 @@ func TestPropose_InvalidDependency(t *testing.T) {
 		"",
 		[]string{"does-not-exist", "also-missing"}, // These don't exist
 		3,
+		0,
 	)
 	// Should NOT error - invalid deps are skipped with warning
 	if err != nil {
@@ -868,13 +994,13 @@ func TestPropose_KindDeterminesRelation(t *testing.T) {
 	}
 
 	// Propose system hypothesis - should create componentOf
-	_, err = tools.ProposeHypothesis("System Hypo", "A system thing", "global", "system", "{}", "", []string{"base-claim"}, 3)
+	_, err = tools.ProposeHypothesis(ctx, "System Hypo", "A system thing", "global", "system", "{}", "", []string{"base-claim"}, 3, 0)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for system failed: %v", err)
 	}
 
 	// Propose episteme hypothesis - should create constituentOf
-	_, err = tools.ProposeHypothesis("Episteme Hypo", "An epistemic claim", "global", "episteme", "{}", "", []string{"base-claim"}, 3)
+	_, err = tools.ProposeHypothesis(ctx, "Episteme Hypo", "An epistemic claim", "global", "episteme", "{}", "", []string{"base-claim"}, 3, 0)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis for episteme failed: %v", err)
 	}
@@ -926,7 +1052,7 @@ func TestWLNK_MemberOf_NoPropagation(t *testing.T) {
 	}
 
 	// Create good hypothesis that is member of bad decision
-	_, err = tools.ProposeHypothesis(
+	_, err = tools.ProposeHypothesis(ctx,
 		"Good Member",
 		"A good hypothesis",
 		"global",
@@ -935,6 +1061,7 @@ func TestWLNK_MemberOf_NoPropagation(t *testing.T) {
 		"bad-decision", // MemberOf the bad decision
 		nil,
 		3,
+		0,
 	)
 	if err != nil {
 		t.Fatalf("ProposeHypothesis failed: %v", err)
@@ -947,7 +1074,7 @@ func TestWLNK_MemberOf_NoPropagation(t *testing.T) {
 	}
 
 	// Calculate R for good-member
-	result, err := tools.CalculateR("good-member")
+	result, err := tools.CalculateR(ctx, "good-member")
 	if err != nil {
 		t.Fatalf("CalculateR failed: %v", err)
 	}
@@ -1003,3 +1130,116 @@ func TestFormatInvariants(t *testing.T) {
 		t.Errorf("Expected line 3 to start with '3. Telethon', got: %s", lines[2])
 	}
 }
+
+func TestCheckGraph(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	result, err := tools.CheckGraph(ctx)
+	if err != nil {
+		t.Fatalf("CheckGraph failed: %v", err)
+	}
+	if !strings.Contains(result, "Graph OK") {
+		t.Errorf("Expected clean graph, got: %s", result)
+	}
+
+	for _, id := range []string{"cyc-a", "cyc-b"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L1", id, "Content", "ctx", "global", ""); err != nil {
+			t.Fatalf("Failed to create holon: %v", err)
+		}
+	}
+	// Inserted directly: createRelation refuses to close a cycle
+	_ = tools.DB.CreateRelation(ctx, "cyc-a", "dependsOn", "cyc-b", 3)
+	_ = tools.DB.CreateRelation(ctx, "cyc-b", "dependsOn", "cyc-a", 3)
+
+	result, err = tools.CheckGraph(ctx)
+	if err != nil {
+		t.Fatalf("CheckGraph failed: %v", err)
+	}
+	if !strings.Contains(result, "CYCLES (1)") || !strings.Contains(result, "cyc-a ↔ cyc-b") {
+		t.Errorf("Expected cycle cyc-a ↔ cyc-b, got: %s", result)
+	}
+}
+
+func TestWhatIf(t *testing.T) {
+	tools, _, _ := setupTools(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"wi-app", "wi-lib"} {
+		if err := tools.DB.CreateHolon(ctx, id, "hypothesis", "system", "L2", id, "Content", "ctx", "global", ""); err != nil {
+			t.Fatalf("Failed to create holon: %v", err)
+		}
+		if err := tools.DB.AddEvidence(ctx, "e-"+id, id, "test", "ok", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+			t.Fatalf("Failed to add evidence: %v", err)
+		}
+	}
+	_ = tools.DB.CreateRelation(ctx, "wi-app", "dependsOn", "wi-lib", 1)
+
+	result, err := tools.WhatIf(ctx, "wi-app", `[{"op": "set_cl", "holon_id": "wi-app", "dependency_id": "wi-lib", "cl": 3}]`)
+	if err != nil {
+		t.Fatalf("WhatIf failed: %v", err)
+	}
+	if !strings.Contains(result, "R_eff: 0.60 → 1.00 (Δ +0.40)") {
+		t.Errorf("Expected R_eff 0.60 → 1.00, got: %s", result)
+	}
+	if !strings.Contains(result, "Weakest Link: wi-lib → (self)") {
+		t.Errorf("Expected weakest link to move off wi-lib, got: %s", result)
+	}
+
+	holon, _ := tools.DB.GetHolon(ctx, "wi-app")
+	if holon.CachedRScore.Valid && holon.CachedRScore.Float64 == 1.0 {
+		t.Error("WhatIf persisted the simulated score")
+	}
+
+	result, err = tools.WhatIf(ctx, "wi-app", "")
+	if err != nil {
+		t.Fatalf("WhatIf sensitivity failed: %v", err)
+	}
+	if !strings.Contains(result, "## Sensitivity: wi-app") || !strings.Contains(result, "set CL of wi-app → wi-lib to CL3") {
+		t.Errorf("Expected CL change in sensitivity table, got: %s", result)
+	}
+
+	if _, err := tools.WhatIf(ctx, "wi-app", `[{"op": "drop_table"}]`); err == nil {
+		t.Error("Expected error for unknown change")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tools, fsm, _ := setupTools(t)
+	server := NewServer(tools)
+	fsm.State.Phase = PhaseAbduction
+
+	if _, err := tools.ProposeHypothesis(t.Context(), "Redis cache", "Read-through caching of sessions in Redis", "global", "system", "{}", "", nil, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if _, err := tools.ProposeHypothesis(t.Context(), "Sticky sessions", "Pin users to one node", "global", "system", "{}", "", nil, 3, 0); err != nil {
+		t.Fatalf("ProposeHypothesis failed: %v", err)
+	}
+	if err := tools.DB.AddEvidence(t.Context(), "ev-bench", "sticky-sessions", "test", "Node failover dropped 3% of carts", "fail", "L1", "test-runner", ""); err != nil {
+		t.Fatalf("AddEvidence failed: %v", err)
+	}
+	if _, err := tools.FinalizeDecision(t.Context(), "Session storage", "redis-cache", []string{"sticky-sessions"}, "Sessions are lost on failover", "Store sessions in Redis", "Failover keeps carts", "Redis is now critical", ""); err != nil {
+		t.Fatalf("FinalizeDecision failed: %v", err)
+	}
+
+	result, err := tools.Search(t.Context(), "failover", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	found := map[string]SearchHit{}
+	for _, h := range result.Hits {
+		found[h.Source] = h
+	}
+	if h := found["evidence"]; h.HolonID != "sticky-sessions" || h.EvidenceID != "ev-bench" || h.URI != holonURI("sticky-sessions") {
+		t.Errorf("Expected the evidence hit linked to sticky-sessions, got %+v", result.Hits)
+	}
+	if h := found["drr"]; h.HolonID != "session-storage" || h.URI != drrURI("session-storage") {
+		t.Errorf("Expected the DRR body to be searchable, got %+v", result.Hits)
+	}
+
+	structured := callStructured(t, server, "quint_search", `{"query":"read-through redis"}`)
+	hits, _ := structured["hits"].([]interface{})
+	if len(hits) != 1 || hits[0].(map[string]interface{})["holon_id"] != "redis-cache" {
+		t.Errorf("Expected redis-cache as the only hit, got %+v", structured)
+	}
+}
@@ -0,0 +1,537 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+)
+
+// browser is the state of the terminal UI: a holon list per layer tab, and a stack of holon
+// pages drilled into from it. It does no terminal I/O; Run feeds it keys and draws its view.
+type browser struct {
+	ctx   context.Context
+	tools *fpf.Tools
+	now   func() time.Time
+
+	snap     *snapshot
+	stamp    string            // changeStamp of the project when snap was loaded
+	layer    int               // Index of the current tab in snap.layers
+	selected map[string]string // Selected holon per layer, kept by ID across reloads
+	pages    []*page           // Drill-down path; empty shows the layer list
+	prompt   *prompt
+	status   string
+	quit     bool
+}
+
+// page is a holon detail view with the cursor over its dependencies, dependents and evidence
+type page struct {
+	id     string
+	cursor int
+}
+
+type itemKind int
+
+const (
+	dependencyItem itemKind = iota
+	dependentItem
+	evidenceItem
+)
+
+type pageItem struct {
+	kind itemKind
+	id   string
+}
+
+// prompt is an open question in the status line: a y/n confirmation or a line of text
+type prompt struct {
+	label   string
+	input   string
+	confirm bool
+	submit  func(input string)
+}
+
+// key is one key press; name is empty for printable runes
+type key struct {
+	name string // up, down, left, right, enter, esc, backspace, tab, ctrl+c
+	r    rune
+}
+
+func newBrowser(ctx context.Context, tools *fpf.Tools) (*browser, error) {
+	if tools.DB == nil {
+		return nil, fmt.Errorf("DB not initialized")
+	}
+	b := &browser{
+		ctx:      ctx,
+		tools:    tools,
+		now:      time.Now,
+		selected: make(map[string]string),
+	}
+	if err := b.reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// reload re-reads the graph and the project policy, keeping the current tab, selection and drill-down path
+func (b *browser) reload() error {
+	stamp := changeStamp(b.tools.GetFPFDir())
+	policy, err := b.tools.FSM.AssurancePolicy()
+	if err != nil {
+		return err
+	}
+	calc := assurance.NewWithPolicy(b.tools.DB.GetRawDB(), policy)
+	snap, err := loadSnapshot(b.ctx, b.tools.DB, calc, b.now())
+	if err != nil {
+		return err
+	}
+	b.snap, b.stamp = snap, stamp
+	if b.layer >= len(snap.layers) {
+		b.layer = 0
+	}
+	for _, p := range b.pages {
+		p.cursor = clamp(p.cursor, len(b.items(p)))
+	}
+	return nil
+}
+
+func (b *browser) currentLayer() string {
+	return b.snap.layers[b.layer]
+}
+
+// cursor returns the index of the selected holon in the current layer
+func (b *browser) cursor() int {
+	holons := b.snap.byLayer[b.currentLayer()]
+	for i, h := range holons {
+		if h.ID == b.selected[b.currentLayer()] {
+			return i
+		}
+	}
+	return 0
+}
+
+func (b *browser) selectedHolon() *holonView {
+	holons := b.snap.byLayer[b.currentLayer()]
+	if len(holons) == 0 {
+		return nil
+	}
+	return holons[b.cursor()]
+}
+
+func (b *browser) moveCursor(delta int) {
+	holons := b.snap.byLayer[b.currentLayer()]
+	if len(holons) == 0 {
+		return
+	}
+	i := clamp(b.cursor()+delta, len(holons))
+	b.selected[b.currentLayer()] = holons[i].ID
+}
+
+// items lists what can be selected on a holon page, in display order
+func (b *browser) items(p *page) []pageItem {
+	h := b.snap.holons[p.id]
+	if h == nil {
+		return nil
+	}
+	var items []pageItem
+	for _, d := range h.Deps {
+		items = append(items, pageItem{kind: dependencyItem, id: d.ID})
+	}
+	for _, id := range h.Dependents {
+		items = append(items, pageItem{kind: dependentItem, id: id})
+	}
+	for _, e := range h.Evidence {
+		items = append(items, pageItem{kind: evidenceItem, id: e.ID})
+	}
+	return items
+}
+
+func (b *browser) currentPage() *page {
+	if len(b.pages) == 0 {
+		return nil
+	}
+	return b.pages[len(b.pages)-1]
+}
+
+func (b *browser) open(id string) {
+	b.pages = append(b.pages, &page{id: id})
+	b.status = ""
+}
+
+// handle applies one key press
+func (b *browser) handle(k key) {
+	if k.name == "ctrl+c" {
+		b.quit = true
+		return
+	}
+	if b.prompt != nil {
+		b.handlePrompt(k)
+		return
+	}
+
+	switch {
+	case k.r == 'q':
+		b.quit = true
+		return
+	case k.r == 'r':
+		b.refresh()
+		return
+	}
+
+	p := b.currentPage()
+	if p == nil {
+		b.handleList(k)
+		return
+	}
+	items := b.items(p)
+	switch {
+	case k.name == "up" || k.r == 'k':
+		p.cursor = clamp(p.cursor-1, len(items))
+	case k.name == "down" || k.r == 'j':
+		p.cursor = clamp(p.cursor+1, len(items))
+	case k.name == "enter" || k.name == "right" || k.r == 'l':
+		if p.cursor < len(items) && items[p.cursor].kind != evidenceItem {
+			b.open(items[p.cursor].id)
+		}
+	case k.name == "esc" || k.name == "backspace" || k.name == "left" || k.r == 'h':
+		b.pages = b.pages[:len(b.pages)-1]
+		b.status = ""
+	case k.r == 'd':
+		b.confirmDeprecate(p.id)
+	case k.r == 'w':
+		if p.cursor < len(items) && items[p.cursor].kind == evidenceItem {
+			b.askWaiver(items[p.cursor].id)
+		} else {
+			b.status = "Select an evidence item to waive"
+		}
+	}
+}
+
+func (b *browser) handleList(k key) {
+	switch {
+	case k.name == "up" || k.r == 'k':
+		b.moveCursor(-1)
+	case k.name == "down" || k.r == 'j':
+		b.moveCursor(1)
+	case k.name == "left" || k.r == 'h':
+		b.layer = (b.layer + len(b.snap.layers) - 1) % len(b.snap.layers)
+	case k.name == "right" || k.name == "tab" || k.r == 'l':
+		b.layer = (b.layer + 1) % len(b.snap.layers)
+	case k.name == "enter":
+		if h := b.selectedHolon(); h != nil {
+			b.open(h.ID)
+		}
+	case k.r == 'd':
+		if h := b.selectedHolon(); h != nil {
+			b.confirmDeprecate(h.ID)
+		}
+	}
+}
+
+func (b *browser) handlePrompt(k key) {
+	p := b.prompt
+	if p.confirm {
+		b.prompt = nil
+		if k.r == 'y' || k.r == 'Y' {
+			p.submit("")
+		} else {
+			b.status = "Cancelled"
+		}
+		return
+	}
+	switch k.name {
+	case "esc":
+		b.prompt = nil
+		b.status = "Cancelled"
+	case "enter":
+		b.prompt = nil
+		p.submit(strings.TrimSpace(p.input))
+	case "backspace":
+		if runes := []rune(p.input); len(runes) > 0 {
+			p.input = string(runes[:len(runes)-1])
+		}
+	case "":
+		p.input += string(k.r)
+	}
+}
+
+// poll reloads the snapshot when the project changed on disk, for example through the MCP
+// server, or when the snapshot is older than maxSnapshotAge
+func (b *browser) poll() {
+	if changeStamp(b.tools.GetFPFDir()) == b.stamp && b.now().Sub(b.snap.loadedAt) < maxSnapshotAge {
+		return
+	}
+	if err := b.reload(); err != nil {
+		b.status = "Refresh failed: " + err.Error()
+	}
+}
+
+func (b *browser) refresh() {
+	if err := b.reload(); err != nil {
+		b.status = "Refresh failed: " + err.Error()
+		return
+	}
+	b.status = "Refreshed"
+}
+
+func (b *browser) confirmDeprecate(id string) {
+	h := b.snap.holons[id]
+	if h == nil {
+		return
+	}
+	if h.Layer != "L2" && h.Layer != "L1" {
+		b.status = fmt.Sprintf("Cannot deprecate %s from %s (only L2 and L1 can be deprecated)", id, h.Layer)
+		return
+	}
+	b.prompt = &prompt{
+		label:   fmt.Sprintf("Deprecate %s (%s → %s)? [y/N]", id, h.Layer, map[string]string{"L2": "L1", "L1": "L0"}[h.Layer]),
+		confirm: true,
+		submit: func(string) {
+			result, err := b.tools.Decay(b.ctx, id, "", "", "")
+			if err != nil {
+				b.status = "Deprecate failed: " + err.Error()
+				return
+			}
+			d := result.Deprecated
+			b.afterAction(fmt.Sprintf("Deprecated %s: %s → %s", d.HolonID, d.From, d.To))
+		},
+	}
+}
+
+// askWaiver asks for the waiver end date, then for its rationale
+func (b *browser) askWaiver(evidenceID string) {
+	b.prompt = &prompt{
+		label: fmt.Sprintf("Waive %s until (YYYY-MM-DD):", evidenceID),
+		submit: func(until string) {
+			b.prompt = &prompt{
+				label: "Rationale:",
+				submit: func(rationale string) {
+					result, err := b.tools.Decay(b.ctx, "", evidenceID, until, rationale)
+					if err != nil {
+						b.status = "Waive failed: " + err.Error()
+						return
+					}
+					b.afterAction(fmt.Sprintf("Waived %s until %s", result.Waiver.EvidenceID, result.Waiver.WaivedUntil))
+				},
+			}
+		},
+	}
+}
+
+func (b *browser) afterAction(status string) {
+	if err := b.reload(); err != nil {
+		status += " (refresh failed: " + err.Error() + ")"
+	}
+	b.status = status
+}
+
+// Line styles
+const (
+	plain    = ""
+	bold     = "\x1b[1m"
+	reverse  = "\x1b[7m"
+	dim      = "\x1b[2m"
+	warning  = "\x1b[33m"
+	styleEnd = "\x1b[0m"
+)
+
+type line struct {
+	text  string
+	style string
+}
+
+// view renders the browser as exactly height lines of at most width columns
+func (b *browser) view(width, height int) []string {
+	header := []line{
+		{text: fmt.Sprintf("quint-code · Holons: %d · updated %s", len(b.snap.holons), b.snap.loadedAt.Format(time.TimeOnly)), style: bold},
+		{text: b.tabs()},
+		{},
+	}
+
+	var body []line
+	var cursorLine int
+	var help string
+	if p := b.currentPage(); p != nil {
+		body, cursorLine = b.pageLines(p)
+		help = "↑↓ select · enter open · esc back · d deprecate · w waive evidence · r refresh · q quit"
+	} else {
+		body, cursorLine = b.listLines()
+		help = "←→ layer · ↑↓ select · enter open · d deprecate · r refresh · q quit"
+	}
+
+	footer := []line{{text: b.status}, {text: help, style: dim}}
+	if b.prompt != nil {
+		footer[0] = line{text: b.prompt.label + " " + b.prompt.input, style: bold}
+	}
+
+	bodyHeight := max(height-len(header)-len(footer), 0)
+	offset := 0
+	if cursorLine >= bodyHeight {
+		offset = cursorLine - bodyHeight + 1
+	}
+	body = body[min(offset, len(body)):]
+	body = body[:min(bodyHeight, len(body))]
+	for len(body) < bodyHeight {
+		body = append(body, line{})
+	}
+
+	var out []string
+	for _, l := range append(append(header, body...), footer...) {
+		text := truncate(l.text, width)
+		if l.style != plain && text != "" {
+			text = l.style + text + styleEnd
+		}
+		out = append(out, text)
+	}
+	return out[:min(len(out), height)]
+}
+
+func (b *browser) tabs() string {
+	var tabs []string
+	for i, layer := range b.snap.layers {
+		tab := fmt.Sprintf("%s (%d)", layer, len(b.snap.byLayer[layer]))
+		if i == b.layer {
+			tab = "[" + tab + "]"
+		} else {
+			tab = " " + tab + " "
+		}
+		tabs = append(tabs, tab)
+	}
+	return strings.Join(tabs, " ")
+}
+
+func (b *browser) listLines() ([]line, int) {
+	holons := b.snap.byLayer[b.currentLayer()]
+	if len(holons) == 0 {
+		return []line{{text: "  No holons in " + b.currentLayer(), style: dim}}, 0
+	}
+	idWidth := 0
+	for _, h := range holons {
+		idWidth = max(idWidth, min(len([]rune(h.ID)), 32))
+	}
+	cursor := b.cursor()
+	lines := make([]line, len(holons))
+	for i, h := range holons {
+		text := fmt.Sprintf("  %s  R %.2f  F%d  %s", pad(h.ID, idWidth), h.R, h.Formality, h.Title)
+		style := plain
+		if h.Stale > 0 {
+			text += fmt.Sprintf("  ⚠ %d stale, R −%.2f", h.Stale, h.DecayPenalty)
+			style = warning
+		}
+		if i == cursor {
+			text = "▸" + text[1:]
+			style = reverse
+		}
+		lines[i] = line{text: text, style: style}
+	}
+	return lines, cursor
+}
+
+func (b *browser) pageLines(p *page) ([]line, int) {
+	h := b.snap.holons[p.id]
+	if h == nil {
+		return []line{{text: fmt.Sprintf("Holon %s no longer exists", p.id), style: warning}}, 0
+	}
+
+	lines := []line{
+		{text: fmt.Sprintf("%s — %s", h.ID, h.Title), style: bold},
+		{text: fmt.Sprintf("Layer %s · kind %s · scope %s", h.Layer, orNone(h.Kind), orNone(h.Scope))},
+		{text: fmt.Sprintf("R_eff %.2f (self %.2f) · F%d · weakest link %s", h.R, h.SelfScore, h.Formality, orNone(h.WeakestLink))},
+	}
+	decay := line{text: "Decay: evidence fresh"}
+	switch {
+	case h.Stale > 0:
+		decay = line{text: fmt.Sprintf("Decay: %d expired evidence, penalty %.2f", h.Stale, h.DecayPenalty), style: warning}
+	case h.DecayPenalty > 0:
+		decay.text = fmt.Sprintf("Decay: penalty %.2f from dependencies", h.DecayPenalty)
+	}
+	lines = append(lines, decay)
+
+	cursorLine, index := 0, 0
+	item := func(text, style string) {
+		if index == p.cursor {
+			cursorLine = len(lines)
+			text, style = "▸"+text[1:], reverse
+		}
+		lines = append(lines, line{text: text, style: style})
+		index++
+	}
+	section := func(title string, n int) {
+		lines = append(lines, line{}, line{text: fmt.Sprintf("%s (%d)", title, n), style: bold})
+		if n == 0 {
+			lines = append(lines, line{text: "  none", style: dim})
+		}
+	}
+
+	section("Dependencies", len(h.Deps))
+	for _, d := range h.Deps {
+		item(fmt.Sprintf("  %s  CL%d  %s", d.ID, d.CL, b.summary(d.ID)), plain)
+	}
+	section("Dependents", len(h.Dependents))
+	for _, id := range h.Dependents {
+		item(fmt.Sprintf("  %s  %s", id, b.summary(id)), plain)
+	}
+	section("Evidence", len(h.Evidence))
+	for _, e := range h.Evidence {
+		text := fmt.Sprintf("  %s  %s  %s", e.ID, e.Type, e.Verdict)
+		style := plain
+		switch {
+		case e.ValidUntil == "":
+			text += "  no expiry"
+		case e.Status == evidenceWaived:
+			text += fmt.Sprintf("  expired %s, waived until %s", e.ValidUntil, e.WaivedTill)
+		case e.Status == evidenceDecaying:
+			text += fmt.Sprintf("  decaying since %s, %.0f%% left", e.ValidUntil, e.Retained*100)
+			style = warning
+		case e.Status == evidenceExpired:
+			text += fmt.Sprintf("  EXPIRED %s", e.ValidUntil)
+			style = warning
+		default:
+			text += "  valid until " + e.ValidUntil
+		}
+		item(text, style)
+	}
+	return lines, cursorLine
+}
+
+// summary describes a linked holon in one line
+func (b *browser) summary(id string) string {
+	h := b.snap.holons[id]
+	if h == nil {
+		return "(missing)"
+	}
+	return fmt.Sprintf("%s  R %.2f  %s", h.Layer, h.R, h.Title)
+}
+
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	return max(i, 0)
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:max(width, 0)])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-len([]rune(s)))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,254 @@
+package tui
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+)
+
+func setupBrowser(t *testing.T) *browser {
+	t.Helper()
+	tempDir := t.TempDir()
+	quintDir := filepath.Join(tempDir, ".quint")
+	if err := os.MkdirAll(quintDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	database, err := db.NewStore(filepath.Join(quintDir, "quint.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize DB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	fsm := &fpf.FSM{State: fpf.State{Phase: fpf.PhaseIdle}, DB: database.GetRawDB()}
+	tools := fpf.NewTools(fsm, tempDir, database)
+	if err := tools.InitProject(); err != nil {
+		t.Fatalf("Failed to initialize project: %v", err)
+	}
+
+	ctx := t.Context()
+	holons := []struct{ id, layer, title string }{
+		{"redis-cache", "L2", "Redis cache"},
+		{"api-gateway", "L2", "API gateway"},
+		{"cdn-edge", "L1", "CDN edge"},
+	}
+	for _, h := range holons {
+		if err := database.CreateHolon(ctx, h.id, "hypothesis", "system", h.layer, h.title, "content", "default", "global", ""); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(quintDir, "knowledge", h.layer, h.id+".md")
+		if err := os.WriteFile(path, []byte("# "+h.title), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.CreateRelation(ctx, "redis-cache", "componentOf", "api-gateway", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddEvidence(ctx, "e-fresh", "api-gateway", "test", "ok", "pass", "L2", "test-runner", "2099-12-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddEvidence(ctx, "e-stale", "redis-cache", "test", "ok", "pass", "L2", "test-runner", "2000-01-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := newBrowser(ctx, tools)
+	if err != nil {
+		t.Fatalf("newBrowser failed: %v", err)
+	}
+	return b
+}
+
+func press(b *browser, keys ...key) {
+	for _, k := range keys {
+		b.handle(k)
+	}
+}
+
+func typeText(b *browser, text string) {
+	for _, r := range text {
+		b.handle(key{r: r})
+	}
+	b.handle(key{name: "enter"})
+}
+
+func screen(b *browser) string {
+	return strings.Join(b.view(120, 40), "\n")
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	b := setupBrowser(t)
+
+	if got := strings.Join(b.snap.layers, " "); got != "L0 L1 L2 DRR invalid" {
+		t.Errorf("Expected the fixed layer tabs, got %q", got)
+	}
+	redis := b.snap.holons["redis-cache"]
+	if redis.Stale != 1 || redis.Evidence[0].Status != evidenceExpired || redis.DecayPenalty == 0 {
+		t.Errorf("Expected redis-cache to have one expired evidence and a decay penalty, got %+v", redis)
+	}
+	if len(redis.Dependents) != 1 || redis.Dependents[0] != "api-gateway" {
+		t.Errorf("Expected api-gateway to depend on redis-cache, got %v", redis.Dependents)
+	}
+	gateway := b.snap.holons["api-gateway"]
+	if len(gateway.Deps) != 1 || gateway.Deps[0].ID != "redis-cache" || gateway.Deps[0].CL != 3 {
+		t.Errorf("Expected api-gateway to depend on redis-cache with CL3, got %+v", gateway.Deps)
+	}
+	if gateway.WeakestLink != "redis-cache" || gateway.R > redis.R {
+		t.Errorf("Expected redis-cache to cap api-gateway (R %.2f vs %.2f), weakest link %q", gateway.R, redis.R, gateway.WeakestLink)
+	}
+}
+
+func TestLoadSnapshot_DecayCurves(t *testing.T) {
+	b := setupBrowser(t)
+	now := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	policy := `{"decay": {"Test": {"curve": "linear", "window_days": 40}}}`
+	if err := os.WriteFile(filepath.Join(b.tools.GetFPFDir(), assurance.PolicyFile), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.tools.DB.AddEvidence(t.Context(), "e-aging", "cdn-edge", "test", "ok", "pass", "L1", "test-runner", "2026-03-01T00:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+
+	// Ten days into a 40-day window a quarter of the score is gone
+	cdn := b.snap.holons["cdn-edge"]
+	if e := cdn.Evidence[0]; cdn.Stale != 1 || e.Status != evidenceDecaying || math.Abs(e.Retained-0.75) > 1e-9 {
+		t.Errorf("Expected e-aging to be decaying with 75%% left, got %+v", cdn)
+	}
+	if e := b.snap.holons["redis-cache"].Evidence[0]; e.Status != evidenceExpired || e.Retained != 0 {
+		t.Errorf("Expected e-stale to be fully decayed, got %+v", e)
+	}
+
+	b.layer = 1
+	press(b, key{name: "enter"})
+	if s := screen(b); !strings.Contains(s, "decaying since 2026-03-01, 75% left") {
+		t.Errorf("Expected the decaying evidence on the cdn-edge page, got:\n%s", s)
+	}
+}
+
+func TestBrowserPoll(t *testing.T) {
+	b := setupBrowser(t)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	if err := b.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+
+	loaded := b.snap
+	b.poll()
+	if b.snap != loaded {
+		t.Error("Expected no reload of an unchanged project")
+	}
+
+	if err := b.tools.DB.AddEvidence(t.Context(), "e-new", "cdn-edge", "test", "ok", "pass", "L1", "test-runner", ""); err != nil {
+		t.Fatal(err)
+	}
+	b.poll()
+	if b.snap == loaded || len(b.snap.holons["cdn-edge"].Evidence) != 1 {
+		t.Fatalf("Expected a reload after the database changed, got %+v", b.snap.holons["cdn-edge"])
+	}
+
+	loaded = b.snap
+	now = now.Add(maxSnapshotAge)
+	b.poll()
+	if b.snap == loaded {
+		t.Error("Expected an old snapshot to be reloaded so decay moves on")
+	}
+}
+
+func TestBrowserNavigation(t *testing.T) {
+	b := setupBrowser(t)
+
+	press(b, key{name: "right"}, key{name: "right"})
+	if b.currentLayer() != "L2" {
+		t.Fatalf("Expected the L2 tab, got %s", b.currentLayer())
+	}
+	if s := screen(b); !strings.Contains(s, "[L2 (2)]") || !strings.Contains(s, "⚠ 1 stale, R −0.90") {
+		t.Errorf("Expected the L2 tab with a stale marker, got:\n%s", s)
+	}
+
+	// api-gateway sorts first; open it and drill into its dependency
+	press(b, key{name: "enter"})
+	if s := screen(b); !strings.Contains(s, "api-gateway — API gateway") || !strings.Contains(s, "weakest link redis-cache") {
+		t.Errorf("Expected the api-gateway page, got:\n%s", s)
+	}
+	press(b, key{name: "enter"})
+	if p := b.currentPage(); p == nil || p.id != "redis-cache" {
+		t.Fatalf("Expected to drill into redis-cache, got %+v", p)
+	}
+	if s := screen(b); !strings.Contains(s, "EXPIRED 2000-01-01") || !strings.Contains(s, "1 expired evidence") {
+		t.Errorf("Expected the expired evidence of redis-cache, got:\n%s", s)
+	}
+
+	press(b, key{name: "esc"}, key{name: "esc"})
+	if len(b.pages) != 0 {
+		t.Errorf("Expected to be back at the layer list, got %d pages", len(b.pages))
+	}
+	press(b, key{r: 'q'})
+	if !b.quit {
+		t.Error("Expected q to quit")
+	}
+
+	if lines := b.view(20, 10); len(lines) != 10 || len([]rune(lines[0])) > 20+len(bold+styleEnd) {
+		t.Errorf("Expected 10 lines cut to the width, got %q", lines)
+	}
+}
+
+func TestBrowserActions(t *testing.T) {
+	b := setupBrowser(t)
+	b.layer = 2
+
+	// Waive the expired evidence of redis-cache: dependencies come first, then dependents, then evidence
+	b.selected["L2"] = "redis-cache"
+	press(b, key{name: "enter"}, key{name: "down"}, key{r: 'w'})
+	if b.prompt == nil {
+		t.Fatalf("Expected a waiver prompt, status %q", b.status)
+	}
+	until := time.Now().AddDate(0, 1, 0).Format(time.DateOnly)
+	typeText(b, until)
+	typeText(b, "Redis upgrade scheduled")
+	if !strings.Contains(b.status, "Waived e-stale until "+until) {
+		t.Fatalf("Expected the waiver to be recorded, status %q", b.status)
+	}
+	if e := b.snap.holons["redis-cache"].Evidence[0]; e.Status != evidenceWaived || e.WaivedTill != until {
+		t.Errorf("Expected e-stale to show as waived after the reload, got %+v", e)
+	}
+
+	// Deprecate asks first and does nothing unless confirmed
+	press(b, key{r: 'd'}, key{r: 'n'})
+	if b.snap.holons["redis-cache"].Layer != "L2" || b.status != "Cancelled" {
+		t.Errorf("Expected the deprecation to be cancelled, status %q", b.status)
+	}
+	press(b, key{r: 'd'}, key{r: 'y'})
+	if b.status != "Deprecated redis-cache: L2 → L1" || b.snap.holons["redis-cache"].Layer != "L1" {
+		t.Errorf("Expected redis-cache in L1, status %q", b.status)
+	}
+
+	// From the list, deprecate it once more into L0, where it cannot go further
+	press(b, key{name: "esc"}, key{name: "left"})
+	b.selected["L1"] = "redis-cache"
+	press(b, key{r: 'd'}, key{r: 'y'}, key{name: "left"}, key{r: 'd'})
+	if b.prompt != nil || !strings.Contains(b.status, "only L2 and L1") {
+		t.Errorf("Expected L0 holons to refuse deprecation, status %q", b.status)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("\x1b[Aj\x1bOB\r\x7f\t\x03é\x1b"))
+	want := []key{{name: "up"}, {r: 'j'}, {name: "down"}, {name: "enter"}, {name: "backspace"}, {name: "tab"}, {name: "ctrl+c"}, {r: 'é'}, {name: "esc"}}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d keys, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Key %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m0n0x41d/quint-code/assurance"
+	"github.com/m0n0x41d/quint-code/db"
+)
+
+// layerOrder is the order of the layer tabs; layers not listed here follow alphabetically
+var layerOrder = []string{"L0", "L1", "L2", "DRR", "invalid"}
+
+// Evidence freshness as shown in the browser. Expired evidence is decaying until its decay
+// curve reaches the floor, and EXPIRED from then on.
+const (
+	evidenceFresh    = "fresh"
+	evidenceDecaying = "decaying"
+	evidenceExpired  = "EXPIRED"
+	evidenceWaived   = "waived"
+)
+
+// maxSnapshotAge is how long the browser keeps a snapshot of an unchanged project, so that
+// evidence decaying over time still shows up
+const maxSnapshotAge = time.Minute
+
+// changeStamp identifies the state of the files a snapshot is read from: the database, its
+// write-ahead log and the assurance policy. Every write changes the size or modification time
+// of one of them, which is far cheaper to check than reloading the graph.
+func changeStamp(quintDir string) string {
+	var stamp strings.Builder
+	for _, name := range []string{"quint.db", "quint.db-wal", assurance.PolicyFile} {
+		if info, err := os.Stat(filepath.Join(quintDir, name)); err == nil {
+			fmt.Fprintf(&stamp, "%s %d %d;", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return stamp.String()
+}
+
+// snapshot is the knowledge graph as the browser shows it, loaded in one pass
+type snapshot struct {
+	layers   []string
+	byLayer  map[string][]*holonView
+	holons   map[string]*holonView
+	loadedAt time.Time
+}
+
+type holonView struct {
+	ID           string
+	Title        string
+	Layer        string
+	Kind         string
+	Scope        string
+	R            float64
+	SelfScore    float64
+	Formality    int
+	WeakestLink  string
+	DecayPenalty float64
+	Stale        int // Expired evidence without an active waiver, decaying or fully decayed
+	Deps         []depView
+	Dependents   []string
+	Evidence     []evidenceView
+}
+
+type depView struct {
+	ID string
+	CL int
+}
+
+type evidenceView struct {
+	ID         string
+	Type       string
+	Verdict    string
+	ValidUntil string
+	Status     string  // fresh, decaying, EXPIRED or waived
+	Retained   float64 // Share of the score above the decay floor still kept, 1 while valid
+	WaivedTill string
+}
+
+// loadSnapshot reads the holons from the store and evaluates R_eff for all of them against one
+// graph snapshot, which also supplies their dependencies, evidence and waivers
+func loadSnapshot(ctx context.Context, store *db.Store, calc *assurance.Calculator, now time.Time) (*snapshot, error) {
+	rows, err := store.ListHolons(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := calc.NewBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph := batch.Graph()
+
+	snap := &snapshot{byLayer: make(map[string][]*holonView), holons: make(map[string]*holonView), loadedAt: now}
+	for _, row := range rows {
+		report, err := batch.Report(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		h := &holonView{
+			ID:           row.ID,
+			Title:        row.Title,
+			Layer:        row.Layer,
+			Kind:         row.Kind.String,
+			Scope:        report.Scope.String(),
+			R:            report.FinalScore,
+			SelfScore:    report.SelfScore,
+			Formality:    report.Formality,
+			WeakestLink:  report.WeakestLink,
+			DecayPenalty: report.DecayPenalty,
+		}
+
+		edges, err := graph.Dependencies(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			h.Deps = append(h.Deps, depView{ID: e.ID, CL: e.CL})
+		}
+
+		evidence, err := graph.Evidence(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		waivers, err := graph.Waivers(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		waivedUntil := make(map[string]time.Time, len(waivers))
+		for _, w := range waivers {
+			if w.Until.After(now) && w.Until.After(waivedUntil[w.EvidenceID]) {
+				waivedUntil[w.EvidenceID] = w.Until
+			}
+		}
+		// Newest first: the snapshot holds evidence in the order it was recorded
+		for i := len(evidence) - 1; i >= 0; i-- {
+			ev := evidenceStatus(evidence[i], calc.Policy.Decay, waivedUntil, now)
+			if ev.Status == evidenceDecaying || ev.Status == evidenceExpired {
+				h.Stale++
+			}
+			h.Evidence = append(h.Evidence, ev)
+		}
+
+		snap.holons[h.ID] = h
+		snap.byLayer[h.Layer] = append(snap.byLayer[h.Layer], h)
+	}
+
+	for _, h := range snap.holons {
+		for _, d := range h.Deps {
+			if dep, ok := snap.holons[d.ID]; ok {
+				dep.Dependents = append(dep.Dependents, h.ID)
+			}
+		}
+	}
+	for _, h := range snap.holons {
+		sort.Strings(h.Dependents)
+	}
+
+	for _, layer := range layerOrder {
+		snap.layers = append(snap.layers, layer)
+	}
+	var extra []string
+	for layer := range snap.byLayer {
+		if !containsLayer(layerOrder, layer) {
+			extra = append(extra, layer)
+		}
+	}
+	sort.Strings(extra)
+	snap.layers = append(snap.layers, extra...)
+	return snap, nil
+}
+
+// evidenceStatus describes how far evidence has decayed along the curve for its type
+func evidenceStatus(e assurance.EvidenceRecord, decay assurance.DecayPolicy, waivedUntil map[string]time.Time, now time.Time) evidenceView {
+	ev := evidenceView{ID: e.ID, Type: e.Type, Verdict: e.Verdict, Status: evidenceFresh, Retained: 1}
+	if e.ValidUntil == nil {
+		return ev
+	}
+	ev.ValidUntil = e.ValidUntil.Format(time.DateOnly)
+	if !e.ValidUntil.Before(now) {
+		return ev
+	}
+	if until, ok := waivedUntil[e.ID]; ok {
+		ev.Status, ev.WaivedTill = evidenceWaived, until.Format(time.DateOnly)
+		return ev
+	}
+	ev.Retained = decay.For(e.Type).Retained(now.Sub(*e.ValidUntil))
+	ev.Status = evidenceDecaying
+	if ev.Retained == 0 {
+		ev.Status = evidenceExpired
+	}
+	return ev
+}
+
+func containsLayer(layers []string, layer string) bool {
+	for _, l := range layers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Package tui is the interactive terminal browser behind `quint-code tui`: holons by layer,
+// their dependencies and evidence, live R_eff and decay, and the deprecate and waive actions.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/m0n0x41d/quint-code/internal/fpf"
+
+	"golang.org/x/term"
+)
+
+// refreshInterval is how often the browser checks the project for changes, so changes made by
+// agents through the MCP server show up while it is open
+const refreshInterval = 2 * time.Second
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	exitAltScreen  = "\x1b[?25h\x1b[?1049l"
+)
+
+// Run shows the browser on the terminal until the user quits or ctx is cancelled
+func Run(ctx context.Context, tools *fpf.Tools, in, out *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return fmt.Errorf("the terminal UI needs an interactive terminal")
+	}
+
+	b, err := newBrowser(ctx, tools)
+	if err != nil {
+		return err
+	}
+	// Warnings written to stderr would tear up the screen
+	tools.Log = fpf.NewLogger(io.Discard)
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(int(in.Fd()), state) }()
+	fmt.Fprint(out, enterAltScreen)
+	defer fmt.Fprint(out, exitAltScreen)
+
+	input := make(chan []byte)
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(input)
+				return
+			}
+			input <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for !b.quit {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		fmt.Fprint(out, "\x1b[H"+strings.Join(b.view(width, height), "\x1b[K\r\n")+"\x1b[K\x1b[J")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			b.poll()
+		case data, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, k := range parseKeys(data) {
+				b.handle(k)
+			}
+		}
+	}
+	return nil
+}
+
+// parseKeys splits raw terminal input into key presses
+func parseKeys(data []byte) []key {
+	var keys []key
+	for len(data) > 0 {
+		switch c := data[0]; {
+		case c == 0x1b:
+			if len(data) >= 3 && (data[1] == '[' || data[1] == 'O') {
+				if name, ok := arrowKeys[data[2]]; ok {
+					keys = append(keys, key{name: name})
+				}
+				data = data[3:]
+				continue
+			}
+			keys = append(keys, key{name: "esc"})
+			data = data[1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, key{name: "enter"})
+			data = data[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, key{name: "backspace"})
+			data = data[1:]
+		case c == '\t':
+			keys = append(keys, key{name: "tab"})
+			data = data[1:]
+		case c == 0x03:
+			keys = append(keys, key{name: "ctrl+c"})
+			data = data[1:]
+		case c < 0x20:
+			data = data[1:]
+		default:
+			r, size := utf8.DecodeRune(data)
+			keys = append(keys, key{r: r})
+			data = data[size:]
+		}
+	}
+	return keys
+}
+
+var arrowKeys = map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left"}
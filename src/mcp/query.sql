@@ -16,12 +16,18 @@ SELECT title FROM holons WHERE id = ? LIMIT 1;
 -- name: ListAllHolonIDs :many
 SELECT id FROM holons;
 
+-- name: ListHolons :many
+SELECT * FROM holons ORDER BY id;
+
 -- name: ListHolonsByLayer :many
 SELECT * FROM holons WHERE layer = ? ORDER BY created_at DESC;
 
 -- name: UpdateHolonLayer :exec
 UPDATE holons SET layer = ?, updated_at = ? WHERE id = ?;
 
+-- name: UpdateHolonFormality :exec
+UPDATE holons SET formality = ?, updated_at = ? WHERE id = ?;
+
 -- name: UpdateHolonRScore :exec
 UPDATE holons SET cached_r_score = ?, updated_at = ? WHERE id = ?;
 
@@ -12,6 +12,7 @@ CREATE TABLE holons (
     scope TEXT,
     parent_id TEXT REFERENCES holons(id),
     cached_r_score REAL DEFAULT 0.0 CHECK(cached_r_score BETWEEN 0.0 AND 1.0),
+    formality INTEGER DEFAULT 0 CHECK(formality BETWEEN 0 AND 9),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -94,6 +95,16 @@ CREATE TABLE fpf_state (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Full-text search over holon, DRR and evidence content (maintained by Store.CreateHolon/AddEvidence)
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    holon_id UNINDEXED,
+    source UNINDEXED,
+    source_id UNINDEXED,
+    title,
+    content,
+    tokenize = 'porter unicode61'
+);
+
 -- Indexes for WLNK traversal
 CREATE INDEX IF NOT EXISTS idx_relations_target ON relations(target_id, relation_type);
 CREATE INDEX IF NOT EXISTS idx_relations_source ON relations(source_id, relation_type);